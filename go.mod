@@ -1,11 +1,16 @@
 module github.com/richardfelkl/go-prometheus-client
 
-go 1.12
+go 1.13
 
 require (
 	github.com/gorilla/mux v1.7.0
-	github.com/pkg/errors v0.8.1
+	github.com/klauspost/compress v1.9.8
+	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.0.0
+	github.com/prometheus/client_model v0.0.0-20190129233127-fd36f4220a90
+	github.com/prometheus/common v0.4.1
 	go.uber.org/atomic v1.3.2 // indirect
 	go.uber.org/multierr v1.1.0 // indirect
 	go.uber.org/zap v1.9.1
+	gopkg.in/yaml.v2 v2.2.1
 )
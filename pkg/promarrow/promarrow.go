@@ -0,0 +1,135 @@
+// Package promarrow converts Prometheus range query results into Apache Arrow records.
+// It is a separate module so that the Arrow dependency stays optional for users of the client.
+package promarrow
+
+import (
+	"sort"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/pkg/errors"
+	"github.com/richardfelkl/go-prometheus-client/pkg/prometheus"
+)
+
+// TimestampColumn name of record column holding sample timestamps
+const TimestampColumn = "timestamp"
+
+// QueryRangeArrow Prometheus query range decoded into Arrow record
+// param: m     - Prometheus client
+// param: query - Prometheus query string
+// param: start - start time of range interval
+// param: end   - end time of range interval
+// param: step  - sampling interval
+// param: mem   - Arrow allocator, memory.DefaultAllocator is used when nil
+// result: arrow.Record - timestamp column followed by one float64 column per series, caller must Release it
+func QueryRangeArrow(m *prometheus.Client, query string, start, end time.Time, step time.Duration, mem memory.Allocator) (arrow.Record, error) {
+	result, resultType, err := m.QueryRangeRequest(query, start, end, step)
+	if err != nil {
+		return nil, errors.Wrap(err, "promarrow: query range failed")
+	}
+
+	streams, err := prometheus.ParseMatrix(result, resultType)
+	if err != nil {
+		return nil, errors.Wrap(err, "promarrow: matrix parsing failed")
+	}
+
+	return NewRecord(streams, mem), nil
+}
+
+// NewRecord builds Arrow record from range vector series
+// param: streams - range vector series
+// param: mem     - Arrow allocator, memory.DefaultAllocator is used when nil
+// result: arrow.Record - one row per distinct timestamp in ascending order, series lacking sample at
+// that timestamp hold null; value columns are named by series selector and carry labels as field metadata
+func NewRecord(streams []prometheus.SampleStream, mem memory.Allocator) arrow.Record {
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+
+	timestamps := distinctTimestamps(streams)
+	rows := make(map[int64]int, len(timestamps))
+	for i, ts := range timestamps {
+		rows[ts] = i
+	}
+
+	fields := make([]arrow.Field, 0, len(streams)+1)
+	fields = append(fields, arrow.Field{Name: TimestampColumn, Type: &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}})
+	for _, stream := range streams {
+		fields = append(fields, arrow.Field{
+			Name:     columnName(stream.Metric),
+			Type:     arrow.PrimitiveTypes.Float64,
+			Nullable: true,
+			Metadata: labelsMetadata(stream.Metric),
+		})
+	}
+
+	builder := array.NewRecordBuilder(mem, arrow.NewSchema(fields, nil))
+	defer builder.Release()
+
+	timestampBuilder := builder.Field(0).(*array.TimestampBuilder)
+	for _, ts := range timestamps {
+		timestampBuilder.Append(arrow.Timestamp(ts))
+	}
+
+	for i, stream := range streams {
+		values := make([]float64, len(timestamps))
+		valid := make([]bool, len(timestamps))
+		for _, pair := range stream.Values {
+			row := rows[milliseconds(pair.Timestamp)]
+			values[row] = pair.Value
+			valid[row] = true
+		}
+		builder.Field(i+1).(*array.Float64Builder).AppendValues(values, valid)
+	}
+
+	return builder.NewRecord()
+}
+
+// distinctTimestamps returns sorted union of sample timestamps in milliseconds
+func distinctTimestamps(streams []prometheus.SampleStream) []int64 {
+	seen := map[int64]struct{}{}
+	for _, stream := range streams {
+		for _, pair := range stream.Values {
+			seen[milliseconds(pair.Timestamp)] = struct{}{}
+		}
+	}
+
+	timestamps := make([]int64, 0, len(seen))
+	for ts := range seen {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	return timestamps
+}
+
+// columnName returns selector of series labels, series without labels such as sum() result is named {}
+func columnName(labels map[string]string) string {
+	name, err := prometheus.Selector("", labels)
+	if err != nil {
+		return "{}"
+	}
+
+	return name
+}
+
+func labelsMetadata(labels map[string]string) arrow.Metadata {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = labels[key]
+	}
+
+	return arrow.NewMetadata(keys, values)
+}
+
+func milliseconds(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
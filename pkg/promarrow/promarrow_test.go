@@ -0,0 +1,73 @@
+package promarrow
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/richardfelkl/go-prometheus-client/pkg/prometheus"
+)
+
+const matrixResponse = `{"status":"success","data":{"resultType":"matrix","result":[` +
+	`{"metric":{"__name__":"up","instance":"a"},"values":[[1554000000,"1"],[1554000015,"1"]]},` +
+	`{"metric":{"__name__":"up","instance":"b"},"values":[[1554000015,"0"],[1554000030,"1"]]}]}}`
+
+func TestQueryRangeArrow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, matrixResponse)
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	m, err := prometheus.NewClientFromParsedURL(u)
+	if err != nil {
+		t.Fatalf("NewClientFromParsedURL() error = %v", err)
+	}
+
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	start := time.Unix(1554000000, 0)
+	record, err := QueryRangeArrow(m, "up", start, start.Add(time.Second*30), time.Second*15, mem)
+	if err != nil {
+		t.Fatalf("QueryRangeArrow() error = %v", err)
+	}
+	defer record.Release()
+
+	if record.NumRows() != 3 || record.NumCols() != 3 {
+		t.Fatalf("QueryRangeArrow() rows = %v, columns = %v, want 3 and 3", record.NumRows(), record.NumCols())
+	}
+	if name := record.ColumnName(1); name != `{__name__="up",instance="a"}` {
+		t.Errorf("QueryRangeArrow() column name = %v", name)
+	}
+
+	a := record.Column(1).(*array.Float64)
+	b := record.Column(2).(*array.Float64)
+	if !a.IsValid(1) || a.IsValid(2) || b.IsValid(0) || b.Value(1) != 0 || b.Value(2) != 1 {
+		t.Errorf("QueryRangeArrow() values = %v, %v", a, b)
+	}
+}
+
+func TestNewRecord_empty(t *testing.T) {
+	record := NewRecord(nil, nil)
+	defer record.Release()
+
+	if record.NumRows() != 0 || record.NumCols() != 1 {
+		t.Errorf("NewRecord() rows = %v, columns = %v, want 0 and 1", record.NumRows(), record.NumCols())
+	}
+}
+
+func TestNewRecord_unlabelled(t *testing.T) {
+	streams := []prometheus.SampleStream{{Values: []prometheus.SamplePair{{Timestamp: time.Unix(1554000000, 0), Value: 1}}}}
+	record := NewRecord(streams, nil)
+	defer record.Release()
+
+	if name := record.ColumnName(1); name != "{}" {
+		t.Errorf("NewRecord() column name = %v, want {}", name)
+	}
+}
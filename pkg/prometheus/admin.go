@@ -0,0 +1,98 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DeleteSeries deletes data of series matching label matchers, requires admin APIs enabled on server
+// param: matches - series selectors, at least one is required
+// param: start   - start time of deleted interval, zero time is omitted
+// param: end     - end time of deleted interval, zero time is omitted
+func (m *Client) DeleteSeries(matches []string, start, end time.Time) error {
+	if len(matches) == 0 {
+		return errors.Errorf("%v: At least one matcher is required", funcInfo())
+	}
+
+	if _, err := m.admin(context.Background(), "admin/tsdb/delete_series", seriesParams(matches, start, end)); err != nil {
+		return errors.Wrapf(err, "%v: delete series failed", funcInfo())
+	}
+
+	return nil
+}
+
+// CleanTombstones removes deleted data from disk, requires admin APIs enabled on server
+func (m *Client) CleanTombstones() error {
+	if _, err := m.admin(context.Background(), "admin/tsdb/clean_tombstones", nil); err != nil {
+		return errors.Wrapf(err, "%v: clean tombstones failed", funcInfo())
+	}
+
+	return nil
+}
+
+// Snapshot creates snapshot of current data, requires admin APIs enabled on server
+// param: skipHead - skips data present in head block not yet compacted to disk
+// result: string - snapshot directory name relative to server snapshots directory
+func (m *Client) Snapshot(skipHead bool) (string, error) {
+	params := url.Values{}
+	if skipHead {
+		params.Set("skip_head", strconv.FormatBool(skipHead))
+	}
+
+	body, err := m.admin(context.Background(), "admin/tsdb/snapshot", params)
+	if err != nil {
+		return "", errors.Wrapf(err, "%v: snapshot failed", funcInfo())
+	}
+
+	var snapshot struct {
+		Data struct {
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+
+	err = json.Unmarshal(body, &snapshot)
+	if err != nil {
+		return "", errors.Wrapf(err, "%v: snapshot unmarshal failed", funcInfo())
+	}
+
+	return snapshot.Data.Name, nil
+}
+
+// admin posts to Prometheus admin API endpoint and returns response body. Admin endpoints answer successful
+// requests with empty 204 response and failures often with plain text, so non-2xx body is surfaced
+// as StatusError snippet, or as APIError when it carries JSON error envelope
+func (m *Client) admin(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	rawURL := m.apiURL(endpoint, params)
+
+	m.logger.Debug("Prometheus request", "query", rawURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: creating request failed", funcInfo())
+	}
+
+	resp, err := m.do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: getting result from Prometheus failed", funcInfo())
+	}
+	defer resp.Body.Close()
+
+	body, err := readBody(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: reading response body failed", funcInfo())
+	}
+	defer releaseBody(body)
+
+	if err := responseStatusError(resp, body.Bytes()); err != nil {
+		return nil, errors.Wrapf(err, "%v: %v request failed", funcInfo(), endpoint)
+	}
+
+	// returned slice must not alias pooled buffer
+	return append([]byte(nil), body.Bytes()...), nil
+}
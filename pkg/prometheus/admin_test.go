@@ -0,0 +1,180 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+func TestClient_DeleteSeries(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+	start := time.Date(2019, 3, 31, 1, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		m          *Client
+		matches    []string
+		handler    func(w http.ResponseWriter, r *http.Request)
+		wantParams url.Values
+		wantCause  error
+		wantErr    bool
+	}{
+		{
+			name:    "Test DeleteSeries unicorn path",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			matches: []string{`up{job="node"}`},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+			wantParams: url.Values{"match[]": {`up{job="node"}`}, "start": {"2019-03-31T01:00:00Z"}},
+		},
+		{
+			name:    "Test DeleteSeries plain text error",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			matches: []string{"up"},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "admin APIs disabled", http.StatusInternalServerError)
+			},
+			wantParams: url.Values{"match[]": {"up"}, "start": {"2019-03-31T01:00:00Z"}},
+			wantCause:  &StatusError{StatusCode: http.StatusInternalServerError, Body: "admin APIs disabled\n"},
+			wantErr:    true,
+		},
+		{
+			name:    "Test DeleteSeries JSON error",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			matches: []string{"up"},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"invalid matcher"}`)
+			},
+			wantParams: url.Values{"match[]": {"up"}, "start": {"2019-03-31T01:00:00Z"}},
+			wantCause:  &APIError{StatusCode: http.StatusBadRequest, Type: "bad_data", Message: "invalid matcher"},
+			wantErr:    true,
+		},
+		{
+			name:    "Test DeleteSeries matchers fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: dataFailhandler,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		var gotMethod string
+		var gotParams url.Values
+		httpServer := startHTTPServer("/api/v1/admin/tsdb/delete_series", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotMethod = r.Method
+			gotParams = r.URL.Query()
+			tt.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.DeleteSeries(tt.matches, start, time.Time{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.DeleteSeries() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantCause != nil && !reflect.DeepEqual(errors.Cause(err), tt.wantCause) {
+				t.Errorf("Client.DeleteSeries() error cause = %#v, want %#v", errors.Cause(err), tt.wantCause)
+			}
+			if tt.wantParams == nil {
+				return
+			}
+			if gotMethod != http.MethodPost {
+				t.Errorf("Client.DeleteSeries() method = %v, want %v", gotMethod, http.MethodPost)
+			}
+			if !reflect.DeepEqual(gotParams, tt.wantParams) {
+				t.Errorf("Client.DeleteSeries() params = %v, want %v", gotParams, tt.wantParams)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+		// POST is not retried on keep-alive connection closed by previous server, so drop stale ones
+		http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+	}
+}
+
+func TestClient_Snapshot(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+
+	tests := []struct {
+		name         string
+		m            *Client
+		skipHead     bool
+		handler      func(w http.ResponseWriter, r *http.Request)
+		want         string
+		wantSkipHead string
+		wantErr      bool
+	}{
+		{
+			name:     "Test Snapshot unicorn path",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			skipHead: true,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"status":"success","data":{"name":"20171210T211224Z-2be650b6d019eb54"}}`)
+			},
+			want:         "20171210T211224Z-2be650b6d019eb54",
+			wantSkipHead: "true",
+		},
+		{
+			name: "Test Snapshot plain text error",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "create snapshot: no space left on device", http.StatusInternalServerError)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		var gotSkipHead string
+		httpServer := startHTTPServer("/api/v1/admin/tsdb/snapshot", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotSkipHead = r.URL.Query().Get("skip_head")
+			tt.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Snapshot(tt.skipHead)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Snapshot() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && !strings.Contains(err.Error(), "no space left on device") {
+				t.Errorf("Client.Snapshot() error = %v, want response body snippet", err)
+			}
+			if got != tt.want {
+				t.Errorf("Client.Snapshot() = %v, want %v", got, tt.want)
+			}
+			if gotSkipHead != tt.wantSkipHead {
+				t.Errorf("Client.Snapshot() skip_head param = %v, want %v", gotSkipHead, tt.wantSkipHead)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+		// POST is not retried on keep-alive connection closed by previous server, so drop stale ones
+		http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+	}
+}
+
+func TestClient_CleanTombstones(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+	m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+
+	httpServer := startHTTPServer("/api/v1/admin/tsdb/clean_tombstones", "9090", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer httpServer.Shutdown(context.Background())
+
+	if err := m.CleanTombstones(); err != nil {
+		t.Errorf("Client.CleanTombstones() error = %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Alert Prometheus active alert
+type Alert struct {
+	Labels      map[string]string
+	Annotations map[string]string
+	State       string
+	ActiveAt    time.Time
+	Value       float64
+}
+
+// UnmarshalJSON decodes Prometheus alert, value is accepted both as string and number
+func (a *Alert) UnmarshalJSON(data []byte) error {
+	var alert struct {
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations"`
+		State       string            `json:"state"`
+		ActiveAt    *time.Time        `json:"activeAt"`
+		Value       json.RawMessage   `json:"value"`
+	}
+
+	err := json.Unmarshal(data, &alert)
+	if err != nil {
+		return errors.Wrapf(err, "%v: alert unmarshal failed", funcInfo())
+	}
+
+	a.Labels = alert.Labels
+	a.Annotations = alert.Annotations
+	a.State = alert.State
+	a.ActiveAt = time.Time{}
+	if alert.ActiveAt != nil {
+		a.ActiveAt = *alert.ActiveAt
+	}
+
+	a.Value = 0
+	if len(alert.Value) > 0 {
+		valueStr := strings.Trim(string(alert.Value), `"`)
+		a.Value, err = strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			return errors.Wrapf(err, "%v: alert value parsing failed", funcInfo())
+		}
+	}
+
+	return nil
+}
+
+// WatchAlerts polls Prometheus active alerts and invokes fn with current alerts on each interval
+// param: ctx      - polling stops when context is done, context error is returned
+// param: interval - polling interval, must be positive
+// param: fn       - called with current alerts after every successful poll
+func (m *Client) WatchAlerts(ctx context.Context, interval time.Duration, fn func([]Alert)) error {
+	return m.watchAlerts(ctx, interval, false, fn)
+}
+
+// WatchAlertChanges polls Prometheus active alerts like WatchAlerts, but invokes fn
+// only for the first poll and whenever the set of alerts or their states change
+func (m *Client) WatchAlertChanges(ctx context.Context, interval time.Duration, fn func([]Alert)) error {
+	return m.watchAlerts(ctx, interval, true, fn)
+}
+
+func (m *Client) watchAlerts(ctx context.Context, interval time.Duration, changesOnly bool, fn func([]Alert)) error {
+	if interval <= 0 {
+		return errors.Errorf("%v: Polling interval must be positive, got %v", funcInfo(), interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	polled := false
+	lastKey := ""
+
+	for {
+		alerts, err := m.alerts(ctx)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			m.logger.Warn("Prometheus alerts polling failed", "error", err)
+		default:
+			key := alertsKey(alerts)
+			if !changesOnly || !polled || key != lastKey {
+				fn(alerts)
+			}
+			polled = true
+			lastKey = key
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Alerts returns Prometheus active alerts
+// result: []Alert - pending and firing alerts with labels, annotations and activation time
+func (m *Client) Alerts() ([]Alert, error) {
+	alerts, err := m.alerts(context.Background())
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: alerts request failed", funcInfo())
+	}
+
+	return alerts, nil
+}
+
+func (m *Client) alerts(ctx context.Context) ([]Alert, error) {
+	data, err := m.getData(ctx, "alerts", nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: alerts request failed", funcInfo())
+	}
+
+	var alerts struct {
+		Alerts []Alert `json:"alerts"`
+	}
+
+	err = json.Unmarshal(data, &alerts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: alerts unmarshal failed", funcInfo())
+	}
+
+	return alerts.Alerts, nil
+}
+
+// alertsKey returns order independent identity of alerts set and their states
+func alertsKey(alerts []Alert) string {
+	keys := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		keys = append(keys, labelsString(alert.Labels)+alert.State)
+	}
+	sort.Strings(keys)
+
+	return strings.Join(keys, "\n")
+}
@@ -0,0 +1,221 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var (
+	pendingAlertResponse = `{"status":"success","data":{"alerts":[` +
+		`{"labels":{"alertname":"HighLatency"},"annotations":{"summary":"High latency"},"state":"pending","activeAt":"2019-03-31T01:30:00Z","value":"1e+00"}]}}`
+	firingAlertsResponse = `{"status":"success","data":{"alerts":[` +
+		`{"labels":{"alertname":"HighLatency"},"annotations":{"summary":"High latency"},"state":"firing","activeAt":"2019-03-31T01:30:00Z","value":"1e+00"},` +
+		`{"labels":{"alertname":"InstanceDown"},"annotations":{},"state":"firing","activeAt":"2019-03-31T01:31:00Z","value":"0"}]}}`
+)
+
+// pollingAlertsHandler returns responses in order, repeating the last one
+func pollingAlertsHandler(responses ...string) func(w http.ResponseWriter, r *http.Request) {
+	polls := 0
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := responses[len(responses)-1]
+		if polls < len(responses) {
+			response = responses[polls]
+		}
+		polls++
+		fmt.Fprint(w, response)
+	}
+}
+
+func TestClient_Alerts(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name    string
+		m       *Client
+		handler func(w http.ResponseWriter, r *http.Request)
+		want    []Alert
+		wantErr bool
+	}{
+		{
+			name: "Test Alerts unicorn path",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, firingAlertsResponse)
+			},
+			want: []Alert{
+				{
+					Labels:      map[string]string{"alertname": "HighLatency"},
+					Annotations: map[string]string{"summary": "High latency"},
+					State:       "firing",
+					ActiveAt:    time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC),
+					Value:       1,
+				},
+				{
+					Labels:      map[string]string{"alertname": "InstanceDown"},
+					Annotations: map[string]string{},
+					State:       "firing",
+					ActiveAt:    time.Date(2019, 3, 31, 1, 31, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "Test Alerts no alerts",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"status":"success","data":{"alerts":[]}}`)
+			},
+			want: []Alert{},
+		},
+		{
+			name:    "Test Alerts data fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: dataFailhandler,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/alerts", "9090", tt.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Alerts()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Alerts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.Alerts() got = %+v, want %+v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_WatchAlerts(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	type args struct {
+		changesOnly bool
+		handler     func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name     string
+		m        *Client
+		args     args
+		interval time.Duration
+		want     []int
+		wantErr  bool
+	}{
+		{
+			name:     "Test WatchAlerts every poll",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:     args{handler: pollingAlertsHandler(pendingAlertResponse, pendingAlertResponse, firingAlertsResponse)},
+			interval: time.Millisecond * 10,
+			want:     []int{1, 1, 2},
+		},
+		{
+			name:     "Test WatchAlertChanges changes only",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:     args{changesOnly: true, handler: pollingAlertsHandler(pendingAlertResponse, pendingAlertResponse, firingAlertsResponse)},
+			interval: time.Millisecond * 10,
+			want:     []int{1, 2},
+		},
+		{
+			name:     "Test WatchAlerts zero interval",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:     args{handler: pollingAlertsHandler(pendingAlertResponse)},
+			interval: 0,
+			wantErr:  true,
+		},
+		{
+			name:     "Test WatchAlertChanges negative interval",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:     args{changesOnly: true, handler: pollingAlertsHandler(pendingAlertResponse)},
+			interval: -time.Second,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/alerts", "9090", tt.args.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var got []int
+			fn := func(alerts []Alert) {
+				got = append(got, len(alerts))
+				if len(alerts) == 2 {
+					cancel()
+				}
+			}
+
+			watch := tt.m.WatchAlerts
+			if tt.args.changesOnly {
+				watch = tt.m.WatchAlertChanges
+			}
+
+			err := watch(ctx, tt.interval, fn)
+			if tt.wantErr {
+				if err == nil || errors.Cause(err) == context.Canceled || got != nil {
+					t.Errorf("Client.WatchAlerts() error = %v, calls = %v, want interval error", err, got)
+				}
+				return
+			}
+			if errors.Cause(err) != context.Canceled {
+				t.Errorf("Client.WatchAlerts() error = %v, want %v", err, context.Canceled)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Client.WatchAlerts() calls = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestAlert_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Alert
+		wantErr bool
+	}{
+		{
+			name: "Test Alert string value",
+			data: `{"labels":{"alertname":"A"},"state":"firing","activeAt":"2019-03-31T01:30:00Z","value":"1.5e+00"}`,
+			want: Alert{Labels: map[string]string{"alertname": "A"}, State: "firing", ActiveAt: time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC), Value: 1.5},
+		},
+		{
+			name: "Test Alert number value",
+			data: `{"labels":{"alertname":"A"},"state":"pending","value":2}`,
+			want: Alert{Labels: map[string]string{"alertname": "A"}, State: "pending", Value: 2},
+		},
+		{
+			name:    "Test Alert value fail",
+			data:    `{"value":"x"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Alert
+			err := got.UnmarshalJSON([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Alert.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.State != tt.want.State || got.Value != tt.want.Value || !got.ActiveAt.Equal(tt.want.ActiveAt) ||
+				labelsString(got.Labels) != labelsString(tt.want.Labels) {
+				t.Errorf("Alert.UnmarshalJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package prometheus
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AlignRange snaps range bounds down to multiples of interval since Unix epoch, e.g. scrape interval,
+// so that evaluation steps land on the same timestamps across queries and interpolation artifacts are avoided.
+// Bounds are floored, end never moves into the future. Non-positive interval leaves bounds unchanged.
+// param: start    - start time of range interval
+// param: end      - end time of range interval
+// param: interval - alignment interval, e.g. server scrape_interval
+// result: time.Time - aligned start
+// result: time.Time - aligned end
+func AlignRange(start, end time.Time, interval time.Duration) (time.Time, time.Time) {
+	if interval <= 0 {
+		return start, end
+	}
+
+	return alignTime(start, interval), alignTime(end, interval)
+}
+
+// AlignRangeToScrape snaps range bounds with AlignRange to global scrape interval of Prometheus configuration
+// param: start - start time of range interval
+// param: end   - end time of range interval
+// result: time.Time - aligned start
+// result: time.Time - aligned end
+func (m *Client) AlignRangeToScrape(start, end time.Time) (time.Time, time.Time, error) {
+	interval, err := m.ScrapeInterval()
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Wrapf(err, "%v: scrape interval request failed", funcInfo())
+	}
+
+	alignedStart, alignedEnd := AlignRange(start, end, interval)
+
+	return alignedStart, alignedEnd, nil
+}
+
+// alignTime floors t to multiple of interval since Unix epoch, keeping location of t
+func alignTime(t time.Time, interval time.Duration) time.Time {
+	nanos := t.UnixNano()
+	remainder := nanos % int64(interval)
+	if remainder < 0 {
+		remainder += int64(interval)
+	}
+
+	return time.Unix(0, nanos-remainder).In(t.Location())
+}
@@ -0,0 +1,126 @@
+package prometheus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestAlignRange(t *testing.T) {
+	type args struct {
+		start    time.Time
+		end      time.Time
+		interval time.Duration
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name: "Test AlignRange 15s interval",
+			args: args{
+				start:    time.Date(2019, 3, 31, 1, 30, 7, 250000000, time.UTC),
+				end:      time.Date(2019, 3, 31, 2, 0, 44, 0, time.UTC),
+				interval: 15 * time.Second,
+			},
+			wantStart: time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC),
+			wantEnd:   time.Date(2019, 3, 31, 2, 0, 30, 0, time.UTC),
+		},
+		{
+			name: "Test AlignRange already aligned",
+			args: args{
+				start:    time.Date(2019, 3, 31, 1, 30, 15, 0, time.UTC),
+				end:      time.Date(2019, 3, 31, 2, 0, 45, 0, time.UTC),
+				interval: 15 * time.Second,
+			},
+			wantStart: time.Date(2019, 3, 31, 1, 30, 15, 0, time.UTC),
+			wantEnd:   time.Date(2019, 3, 31, 2, 0, 45, 0, time.UTC),
+		},
+		{
+			name: "Test AlignRange non-UTC location",
+			args: args{
+				start:    time.Date(2019, 3, 31, 3, 30, 7, 0, time.FixedZone("CEST", 2*60*60)),
+				end:      time.Date(2019, 3, 31, 4, 0, 59, 0, time.FixedZone("CEST", 2*60*60)),
+				interval: 15 * time.Second,
+			},
+			wantStart: time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC),
+			wantEnd:   time.Date(2019, 3, 31, 2, 0, 45, 0, time.UTC),
+		},
+		{
+			name: "Test AlignRange before epoch",
+			args: args{
+				start:    time.Unix(-7, 0),
+				end:      time.Unix(-1, 0),
+				interval: 15 * time.Second,
+			},
+			wantStart: time.Unix(-15, 0),
+			wantEnd:   time.Unix(-15, 0),
+		},
+		{
+			name: "Test AlignRange zero interval",
+			args: args{
+				start: time.Date(2019, 3, 31, 1, 30, 7, 0, time.UTC),
+				end:   time.Date(2019, 3, 31, 2, 0, 44, 0, time.UTC),
+			},
+			wantStart: time.Date(2019, 3, 31, 1, 30, 7, 0, time.UTC),
+			wantEnd:   time.Date(2019, 3, 31, 2, 0, 44, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotEnd := AlignRange(tt.args.start, tt.args.end, tt.args.interval)
+			if !gotStart.Equal(tt.wantStart) {
+				t.Errorf("AlignRange() start = %v, want %v", gotStart, tt.wantStart)
+			}
+			if !gotEnd.Equal(tt.wantEnd) {
+				t.Errorf("AlignRange() end = %v, want %v", gotEnd, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestClient_AlignRangeToScrape(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "Test AlignRangeToScrape unicorn path",
+			config:    "global:\n  scrape_interval: 30s\n",
+			wantStart: time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC),
+			wantEnd:   time.Date(2019, 3, 31, 2, 0, 30, 0, time.UTC),
+		},
+		{
+			name:    "Test AlignRangeToScrape missing scrape interval",
+			config:  "scrape_configs: []\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/status/config", "9090", configHandler(tt.config))
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+
+			gotStart, gotEnd, err := m.AlignRangeToScrape(time.Date(2019, 3, 31, 1, 30, 7, 0, time.UTC), time.Date(2019, 3, 31, 2, 0, 44, 0, time.UTC))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.AlignRangeToScrape() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !gotStart.Equal(tt.wantStart) || !gotEnd.Equal(tt.wantEnd) {
+				t.Errorf("Client.AlignRangeToScrape() = %v, %v, want %v, %v", gotStart, gotEnd, tt.wantStart, tt.wantEnd)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
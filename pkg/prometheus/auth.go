@@ -0,0 +1,56 @@
+package prometheus
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WithBearerToken sets token sent as Authorization: Bearer header with each request,
+// takes precedence over WithBasicAuth
+func WithBearerToken(token string) Option {
+	return func(args *Client) {
+		args.bearerToken = token
+	}
+}
+
+// WithBearerTokenFile sets file bearer token is read from before each request,
+// so that rotated tokens are picked up without recreating Client. Takes precedence over WithBearerToken.
+func WithBearerTokenFile(path string) Option {
+	return func(args *Client) {
+		args.bearerTokenFile = path
+	}
+}
+
+// WithBasicAuth sets credentials sent with each request using HTTP basic authentication,
+// applied only when both are non-empty and no bearer token is configured, bearer token takes precedence
+func WithBasicAuth(username, password string) Option {
+	return func(args *Client) {
+		args.basicAuthUsername = username
+		args.basicAuthPassword = password
+	}
+}
+
+// setAuthorization sets Authorization header of request from configured credentials
+func (m *Client) setAuthorization(req *http.Request) error {
+	token := m.bearerToken
+
+	if m.bearerTokenFile != "" {
+		data, err := ioutil.ReadFile(m.bearerTokenFile)
+		if err != nil {
+			return errors.Wrapf(err, "%v: reading bearer token file failed", funcInfo())
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	switch {
+	case token != "":
+		req.Header.Set("Authorization", "Bearer "+token)
+	case m.basicAuthUsername != "" && m.basicAuthPassword != "":
+		req.SetBasicAuth(m.basicAuthUsername, m.basicAuthPassword)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithBearerToken(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name string
+		m    *Client
+		want string
+	}{
+		{
+			name: "Test WithBearerToken set",
+			m:    NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithBearerToken("secret")),
+			want: "Bearer secret",
+		},
+		{
+			name: "Test WithBearerToken empty",
+			m:    NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithBearerToken("")),
+		},
+	}
+	for _, tt := range tests {
+		var got string
+		httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Get("Authorization")
+			unicornHandler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.m.QueryRequest("QUERY"); err != nil {
+				t.Errorf("Client.QueryRequest() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Authorization = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestWithBearerTokenFile(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	dir, err := ioutil.TempDir("", "prometheus-client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+
+	var got string
+	httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+		unicornHandler(w, r)
+	})
+	defer httpServer.Shutdown(context.Background())
+
+	m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithBearerToken("static"), WithBearerTokenFile(tokenFile))
+
+	if _, _, err := m.QueryRequest("QUERY"); err == nil {
+		t.Errorf("Client.QueryRequest() with missing token file error = nil, want error")
+	}
+
+	for _, token := range []string{"first", "rotated"} {
+		if err := ioutil.WriteFile(tokenFile, []byte(token+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := m.QueryRequest("QUERY"); err != nil {
+			t.Errorf("Client.QueryRequest() error = %v", err)
+			continue
+		}
+		if want := "Bearer " + token; got != want {
+			t.Errorf("Authorization = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWithBasicAuth(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []Option
+		wantUser     string
+		wantPassword string
+		wantBearer   string
+	}{
+		{
+			name:         "Test WithBasicAuth set",
+			opts:         []Option{WithBasicAuth("admin", "s3cr3t-password")},
+			wantUser:     "admin",
+			wantPassword: "s3cr3t-password",
+		},
+		{
+			name: "Test WithBasicAuth missing password",
+			opts: []Option{WithBasicAuth("admin", "")},
+		},
+		{
+			name:       "Test WithBasicAuth bearer token precedence",
+			opts:       []Option{WithBasicAuth("admin", "s3cr3t-password"), WithBearerToken("secret")},
+			wantBearer: "Bearer secret",
+		},
+	}
+	for _, tt := range tests {
+		var gotUser, gotPassword, gotAuthorization string
+		httpServer := startHTTPServer("/api/v1/{endpoint}", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotUser, gotPassword, _ = r.BasicAuth()
+			gotAuthorization = r.Header.Get("Authorization")
+			unicornHandler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.DebugLevel)
+			m := NewClient("http", "127.0.0.1", "9090", append(tt.opts, WithLogger(zap.New(core)))...)
+
+			if _, _, err := m.QueryRequestWithContext(context.Background(), "QUERY"); err != nil {
+				t.Errorf("Client.QueryRequestWithContext() error = %v", err)
+				return
+			}
+			if gotUser != tt.wantUser || gotPassword != tt.wantPassword {
+				t.Errorf("basic auth = %v:%v, want %v:%v", gotUser, gotPassword, tt.wantUser, tt.wantPassword)
+			}
+			if tt.wantBearer != "" && gotAuthorization != tt.wantBearer {
+				t.Errorf("Authorization = %v, want %v", gotAuthorization, tt.wantBearer)
+			}
+
+			for _, entry := range logs.All() {
+				for _, field := range entry.Context {
+					if strings.Contains(fmt.Sprint(field.String, field.Interface), "s3cr3t-password") {
+						t.Errorf("log entry %q field %v contains password", entry.Message, field.Key)
+					}
+				}
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
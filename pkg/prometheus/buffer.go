@@ -0,0 +1,39 @@
+package prometheus
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxPooledBufferSize buffers grown above this size are dropped instead of being pooled
+// so a single huge response does not pin memory
+const maxPooledBufferSize = 4 << 20
+
+var bodyBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// readBody reads response body into pooled buffer. Buffer must be released with releaseBody
+// once decoding is done, decoded results must not alias buffer bytes.
+func readBody(body io.Reader) (*bytes.Buffer, error) {
+	buf := bodyBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	if _, err := buf.ReadFrom(body); err != nil {
+		releaseBody(buf)
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+func releaseBody(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	bodyBufferPool.Put(buf)
+}
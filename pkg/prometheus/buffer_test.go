@@ -0,0 +1,58 @@
+package prometheus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_readBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "Test readBody unicorn path",
+			body: string(unicornResponse),
+		},
+		{
+			name: "Test readBody empty",
+			body: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readBody(strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("readBody() error = %v", err)
+			}
+			if got.String() != tt.body {
+				t.Errorf("readBody() = %v, want %v", got.String(), tt.body)
+			}
+			releaseBody(got)
+		})
+	}
+}
+
+func TestClient_parseResponse_noAlias(t *testing.T) {
+	m := &Client{}
+
+	body, err := readBody(bytes.NewReader(unicornResponse))
+	if err != nil {
+		t.Fatalf("readBody() error = %v", err)
+	}
+
+	got, _, err := m.parseResponse(body.Bytes())
+	if err != nil {
+		t.Fatalf("Client.parseResponse() error = %v", err)
+	}
+	want := string(got)
+
+	// overwrite released buffer the way next pooled read would
+	releaseBody(body)
+	copy(body.Bytes(), bytes.Repeat([]byte("x"), body.Len()))
+
+	if string(got) != want {
+		t.Errorf("Client.parseResponse() result aliases pooled buffer: %v", string(got))
+	}
+}
@@ -0,0 +1,70 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// BuildInfo Prometheus server build information
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision"`
+	Branch    string `json:"branch"`
+	BuildUser string `json:"buildUser"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+// BuildInfo returns Prometheus server build information
+// result: BuildInfo - version details, error wrapping ErrNotSupported when server does not expose them
+func (m *Client) BuildInfo() (BuildInfo, error) {
+	data, err := m.getData(context.Background(), "status/buildinfo", nil)
+	if err != nil {
+		return BuildInfo{}, errors.Wrapf(err, "%v: buildinfo request failed", funcInfo())
+	}
+
+	var buildInfo BuildInfo
+
+	err = json.Unmarshal(data, &buildInfo)
+	if err != nil {
+		return BuildInfo{}, errors.Wrapf(err, "%v: buildinfo unmarshal failed", funcInfo())
+	}
+
+	return buildInfo, nil
+}
+
+// ReplicaBuildInfo concurrently fetches build information of every client, e.g. to detect version skew across HA replicas.
+// Failure of single replica is reported in errs and does not abort the others.
+// param: clients - clients of replicas
+// result: map[string]BuildInfo - build information by endpoint (protocol://address:port/prefix) of replicas which answered
+// result: map[string]error     - errors by endpoint of replicas which failed, empty when all answered
+func ReplicaBuildInfo(clients []*Client) (map[string]BuildInfo, map[string]error) {
+	buildInfos := make(map[string]BuildInfo, len(clients))
+	errs := map[string]error{}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+
+			buildInfo, err := client.BuildInfo()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[client.rootURL()] = err
+				return
+			}
+			buildInfos[client.rootURL()] = buildInfo
+		}(client)
+	}
+	wg.Wait()
+
+	return buildInfos, errs
+}
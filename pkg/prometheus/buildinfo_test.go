@@ -0,0 +1,128 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+func buildInfoHandler(version string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"status":"success","data":{"version":%q,"revision":"3cbf87b","branch":"HEAD",`+
+			`"buildUser":"root@ci","buildDate":"20190328-10:12:47","goVersion":"go1.12.1"}}`, version)
+	}
+}
+
+func TestClient_BuildInfo(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name      string
+		m         *Client
+		handler   func(w http.ResponseWriter, r *http.Request)
+		want      BuildInfo
+		wantCause error
+		wantErr   bool
+	}{
+		{
+			name:    "Test BuildInfo unicorn path",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: buildInfoHandler("2.8.1"),
+			want: BuildInfo{Version: "2.8.1", Revision: "3cbf87b", Branch: "HEAD", BuildUser: "root@ci",
+				BuildDate: "20190328-10:12:47", GoVersion: "go1.12.1"},
+		},
+		{
+			name:      "Test BuildInfo not supported",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler:   http.NotFound,
+			wantCause: ErrNotSupported,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/status/buildinfo", "9090", tt.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.BuildInfo()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.BuildInfo() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantCause != nil && errors.Cause(err) != tt.wantCause {
+				t.Errorf("Client.BuildInfo() error = %v, want %v", err, tt.wantCause)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.BuildInfo() = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestReplicaBuildInfo(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	clients := []*Client{
+		{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+		{protocol: "http", address: "127.0.0.1", port: "9091", logger: NewZapLogger(logger), timeout: time.Second * 30},
+		{protocol: "http", address: "127.0.0.1", port: "9092", logger: NewZapLogger(logger), timeout: time.Second * 30},
+	}
+
+	httpServerA := startHTTPServer("/api/v1/status/buildinfo", "9090", buildInfoHandler("2.8.1"))
+	defer httpServerA.Shutdown(context.Background())
+	httpServerB := startHTTPServer("/api/v1/status/buildinfo", "9091", buildInfoHandler("2.9.0"))
+	defer httpServerB.Shutdown(context.Background())
+	httpServerC := startHTTPServer("/api/v1/status/buildinfo", "9092", http.NotFound)
+	defer httpServerC.Shutdown(context.Background())
+
+	got, errs := ReplicaBuildInfo(clients)
+
+	versions := map[string]string{}
+	for endpoint, buildInfo := range got {
+		versions[endpoint] = buildInfo.Version
+	}
+	if want := map[string]string{"http://127.0.0.1:9090": "2.8.1", "http://127.0.0.1:9091": "2.9.0"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("ReplicaBuildInfo() versions = %v, want %v", versions, want)
+	}
+	if len(errs) != 1 || errors.Cause(errs["http://127.0.0.1:9092"]) != ErrNotSupported {
+		t.Errorf("ReplicaBuildInfo() errs = %v, want ErrNotSupported for http://127.0.0.1:9092", errs)
+	}
+}
+
+func TestReplicaBuildInfo_pathPrefix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a/api/v1/status/buildinfo":
+			buildInfoHandler("2.8.1")(w, r)
+		case "/b/api/v1/status/buildinfo":
+			buildInfoHandler("2.9.0")(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	clients := []*Client{
+		NewClient("http", u.Hostname(), u.Port(), WithPathPrefix("/a")),
+		NewClient("http", u.Hostname(), u.Port(), WithPathPrefix("/b")),
+	}
+
+	got, errs := ReplicaBuildInfo(clients)
+
+	versions := map[string]string{}
+	for endpoint, buildInfo := range got {
+		versions[endpoint] = buildInfo.Version
+	}
+	if want := map[string]string{server.URL + "/a": "2.8.1", server.URL + "/b": "2.9.0"}; !reflect.DeepEqual(versions, want) || len(errs) != 0 {
+		t.Errorf("ReplicaBuildInfo() versions = %v, errs = %v, want %v", versions, errs, want)
+	}
+}
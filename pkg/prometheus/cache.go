@@ -0,0 +1,98 @@
+package prometheus
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithRangeCache caches range query results for ttl, keyed by canonical query and (start, end, step) window,
+// so identical dashboard refreshes within ttl are served without request. Every caller gets its own copy
+// of cached result, so modifying it does not affect other callers.
+func WithRangeCache(ttl time.Duration) Option {
+	return func(args *Client) {
+		args.rangeCache = newRangeCache(ttl)
+	}
+}
+
+type rangeCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]rangeCacheEntry
+}
+
+type rangeCacheEntry struct {
+	result  *QueryResult
+	expires time.Time
+}
+
+func newRangeCache(ttl time.Duration) *rangeCache {
+	return &rangeCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]rangeCacheEntry{},
+	}
+}
+
+// rangeCacheKey returns cache key of range query, query which cannot be canonicalized is used as is
+func rangeCacheKey(query string, start, end time.Time, step string) string {
+	if canonical, err := CanonicalizeQuery(query); err == nil {
+		query = canonical
+	}
+
+	return strings.Join([]string{
+		query,
+		strconv.FormatInt(start.UnixNano(), 10),
+		strconv.FormatInt(end.UnixNano(), 10),
+		step,
+	}, "\x00")
+}
+
+func (c *rangeCache) get(key string) (*QueryResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return copyQueryResult(entry.result), true
+}
+
+func (c *rangeCache) set(key string, result *QueryResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = rangeCacheEntry{result: copyQueryResult(result), expires: now.Add(c.ttl)}
+}
+
+// copyQueryResult returns deep copy of result, so cached entry is not shared with callers
+func copyQueryResult(result *QueryResult) *QueryResult {
+	copied := *result
+	copied.Result = append([]byte(nil), result.Result...)
+	if result.Warnings != nil {
+		copied.Warnings = append([]string(nil), result.Warnings...)
+	}
+	if result.Stats != nil {
+		stats := *result.Stats
+		copied.Stats = &stats
+	}
+
+	return &copied
+}
@@ -0,0 +1,109 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestWithRangeCache(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Unix(1500000000, 0)
+	end := start.Add(time.Hour)
+	response := `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[1500000000,"1"]]}]}}`
+
+	type query struct {
+		query string
+		start time.Time
+		end   time.Time
+		step  time.Duration
+		after time.Duration
+	}
+	tests := []struct {
+		name         string
+		queries      []query
+		wantRequests int
+	}{
+		{
+			name: "Test WithRangeCache hit",
+			queries: []query{
+				{query: "rate(x[5m])", start: start, end: end, step: time.Minute},
+				{query: "rate( x [5m] )", start: start, end: end, step: time.Minute, after: 30 * time.Second},
+			},
+			wantRequests: 1,
+		},
+		{
+			name: "Test WithRangeCache distinct window",
+			queries: []query{
+				{query: "rate(x[5m])", start: start, end: end, step: time.Minute},
+				{query: "rate(x[5m])", start: start.Add(time.Minute), end: end.Add(time.Minute), step: time.Minute},
+				{query: "rate(x[5m])", start: start, end: end, step: 2 * time.Minute},
+			},
+			wantRequests: 3,
+		},
+		{
+			name: "Test WithRangeCache expired",
+			queries: []query{
+				{query: "rate(x[5m])", start: start, end: end, step: time.Minute},
+				{query: "rate(x[5m])", start: start, end: end, step: time.Minute, after: time.Minute},
+			},
+			wantRequests: 2,
+		},
+	}
+	for _, tt := range tests {
+		var requests int
+		httpServer := startHTTPServer("/api/v1/query_range", "9090", func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			fmt.Fprint(w, response)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithRangeCache(time.Minute))
+			now := time.Now()
+			m.rangeCache.now = func() time.Time { return now }
+
+			for _, q := range tt.queries {
+				now = now.Add(q.after)
+				got, resultType, err := m.QueryRangeRequest(q.query, q.start, q.end, q.step)
+				if err != nil {
+					t.Fatalf("Client.QueryRangeRequest() error = %v", err)
+				}
+				if resultType != ResultTypeMatrix || string(got) != `[{"metric":{},"values":[[1500000000,"1"]]}]` {
+					t.Errorf("Client.QueryRangeRequest() got = %s, %v", got, resultType)
+				}
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("Client.QueryRangeRequest() sent %v requests, want %v", requests, tt.wantRequests)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestWithRangeCache_copy(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Unix(1500000000, 0)
+
+	httpServer := startHTTPServer("/api/v1/query_range", "9090", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[1500000000,"1"]]}]}}`)
+	})
+	defer httpServer.Shutdown(context.Background())
+
+	m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithRangeCache(time.Minute))
+
+	for i := 0; i < 3; i++ {
+		got, _, err := m.QueryRangeRequest("up", start, start.Add(time.Hour), time.Minute)
+		if err != nil {
+			t.Fatalf("Client.QueryRangeRequest() error = %v", err)
+		}
+		if string(got) != `[{"metric":{},"values":[[1500000000,"1"]]}]` {
+			t.Errorf("Client.QueryRangeRequest() request %v got = %s, want unmodified result", i, got)
+		}
+		copy(got, "xx")
+	}
+}
@@ -1,59 +1,369 @@
 package prometheus
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
+	promclient "github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
+// DefaultAPIPrefix path prefix of Prometheus HTTP API used unless overridden by WithAPIPrefix
+const DefaultAPIPrefix = "/api/v1"
+
+// DefaultAccept media type requested unless overridden by WithAccept, so that servers
+// able to return other formats, e.g. protobuf, never do so unexpectedly
+const DefaultAccept = "application/json"
+
+// DefaultTimeout timeout used by Client unless overridden by WithTimeout
+const DefaultTimeout = 30 * time.Second
+
+// orgIDHeader header carrying tenant of multi-tenant Prometheus compatible backends
+const orgIDHeader = "X-Scope-OrgID"
+
+const (
+	// ParamQuery standard name of query expression param
+	ParamQuery = "query"
+	// ParamStart standard name of range start param
+	ParamStart = "start"
+	// ParamEnd standard name of range end param
+	ParamEnd = "end"
+	// ParamStep standard name of range step param
+	ParamStep = "step"
+)
+
+// ErrNotSupported returned when Prometheus server does not expose requested endpoint
+var ErrNotSupported = errors.New("endpoint not supported by Prometheus server")
+
+// statusErrorResponse value of 'status' field of failed API response
+const statusErrorResponse = "error"
+
+// statusSuccessResponse value of 'status' field of successful API response
+const statusSuccessResponse = "success"
+
+// APIError error reported by Prometheus in response with status "error"
+type APIError struct {
+	// StatusCode HTTP status code of response, zero when error is reported with 2xx status
+	StatusCode int
+	// Type contains 'errorType' field, e.g. bad_data, timeout, execution
+	Type string
+	// Message contains 'error' field
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("status %v: %v: %v", e.StatusCode, e.Type, e.Message)
+	}
+
+	return e.Type + ": " + e.Message
+}
+
+// maxErrorBodySnippet maximum number of body bytes included in StatusError
+const maxErrorBodySnippet = 512
+
+// StatusError non-2xx response without Prometheus error body, e.g. 503 from proxy in front of Prometheus
+type StatusError struct {
+	// StatusCode HTTP status code of response
+	StatusCode int
+	// Body leading part of response body
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %v: %v", e.StatusCode, e.Body)
+}
+
+// ErrUnexpectedResultType returned when query result type differs from the one expected by caller,
+// e.g. scalar result of query decoded as vector
+var ErrUnexpectedResultType = errors.New("unexpected result type")
+
+// ErrEmptyResponse returned when Prometheus server responds with zero-length body
+var ErrEmptyResponse = errors.New("empty response body")
+
 // Option functional option for ManblockExternalSvcServer methods
 type Option func(*Client)
 
-// WithLogger sets Client logger
+// WithLogger sets Client zap logger
 func WithLogger(logger *zap.Logger) Option {
 	return func(args *Client) {
-		args.logger = logger
+		args.logger = NewZapLogger(logger)
 	}
 }
 
-// WithTimeout sets Client logger
+// WithTimeout sets Client request timeout, DefaultTimeout is used when not set.
+// Zero timeout explicitly disables the timeout.
 func WithTimeout(timeout time.Duration) Option {
 	return func(args *Client) {
 		args.timeout = timeout
 	}
 }
 
+// WithQueryTimeout sets timeout param bounding query evaluation server-side, sent with instant and range queries.
+// It is independent of client-side timeout set by WithTimeout, which should be longer so that server
+// can abort evaluation and answer with timeout error before the client gives up on the request.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(args *Client) {
+		args.queryTimeout = timeout
+	}
+}
+
+// WithPostQueries sends instant and range queries as POST with params in form-encoded body,
+// so long PromQL is not rejected by URL length limits of server or proxies in between.
+// Without it queries are sent as POST only when URL exceeds length found by DetectMaxURLLength,
+// or defaultMaxURLLength when not detected.
+func WithPostQueries() Option {
+	return func(args *Client) {
+		args.postQueries = true
+	}
+}
+
+// WithMetrics registers Client self-metrics (request counters and durations
+// labeled by endpoint and status) in given registerer, registration failures are logged by Client logger
+func WithMetrics(registerer promclient.Registerer) Option {
+	return func(args *Client) {
+		args.metricsRegisterer = registerer
+	}
+}
+
+// WithOrgIDFromEnv sets tenant sent as X-Scope-OrgID header with each request, e.g. to Mimir,
+// read from environment variable once at construction. Empty variable leaves header unset.
+func WithOrgIDFromEnv(varName string) Option {
+	return func(args *Client) {
+		if orgID := os.Getenv(varName); orgID != "" {
+			args.orgID = orgID
+		}
+	}
+}
+
+// WithHTTPClient sets fully configured HTTP client requests are sent with, e.g. with custom transport.
+// Timeout of given client applies, WithTimeout is ignored.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(args *Client) {
+		args.httpClient = httpClient
+	}
+}
+
+// WithAccept sets Accept header sent with API requests, DefaultAccept is used when not set
+func WithAccept(mediaType string) Option {
+	return func(args *Client) {
+		args.accept = mediaType
+	}
+}
+
+// WithHostHeader sets Host header sent with each request while the configured
+// address is still dialed, useful for virtual hosted Prometheus behind shared ingress
+func WithHostHeader(host string) Option {
+	return func(args *Client) {
+		args.hostHeader = host
+	}
+}
+
+// WithPathPrefix sets path prefix Prometheus is served under (e.g. behind reverse proxy)
+func WithPathPrefix(prefix string) Option {
+	return func(args *Client) {
+		args.pathPrefix = prefix
+	}
+}
+
+// WithAPIPrefix sets path prefix of Prometheus HTTP API, DefaultAPIPrefix is used when not set
+func WithAPIPrefix(prefix string) Option {
+	return func(args *Client) {
+		args.apiPrefix = prefix
+	}
+}
+
+// WithStrictMode turns suspicious but valid requests (e.g. range query step
+// not smaller than the queried window) into errors instead of logged warnings
+func WithStrictMode() Option {
+	return func(args *Client) {
+		args.strict = true
+	}
+}
+
+// WithMaxResultSeries sets maximum number of series typed decoders accept,
+// decoding aborts with error as soon as result exceeds it. Zero means no limit.
+func WithMaxResultSeries(n int) Option {
+	return func(args *Client) {
+		args.maxResultSeries = n
+	}
+}
+
+// WithResponseTransformer sets function applied to query response body before it is parsed,
+// e.g. to unwrap result from non-standard envelope of custom backend
+func WithResponseTransformer(transformer func([]byte) ([]byte, error)) Option {
+	return func(args *Client) {
+		args.responseTransformer = transformer
+	}
+}
+
+// WithDataKeyPath sets keys standard Prometheus response is nested under by proxies,
+// e.g. []string{"prometheus"} for {"prometheus": {"status": ..., "data": ...}}. Default is top level.
+func WithDataKeyPath(path []string) Option {
+	return func(args *Client) {
+		args.dataKeyPath = path
+	}
+}
+
+// WithParamName sends standard query param under custom name, e.g. "q" instead of "query" for forks
+// param: standard - standard param name, one of ParamQuery, ParamStart, ParamEnd, ParamStep
+// param: name     - param name the server expects
+func WithParamName(standard, name string) Option {
+	return func(args *Client) {
+		if args.paramNames == nil {
+			args.paramNames = map[string]string{}
+		}
+		args.paramNames[standard] = name
+	}
+}
+
+// WithQueryParamName sends query expression under custom param name
+func WithQueryParamName(name string) Option {
+	return WithParamName(ParamQuery, name)
+}
+
+// WithStartParamName sends range start under custom param name
+func WithStartParamName(name string) Option {
+	return WithParamName(ParamStart, name)
+}
+
+// WithEndParamName sends range end under custom param name
+func WithEndParamName(name string) Option {
+	return WithParamName(ParamEnd, name)
+}
+
+// WithStepParamName sends range step under custom param name
+func WithStepParamName(name string) Option {
+	return WithParamName(ParamStep, name)
+}
+
 // Client Prometheus client struct
 type Client struct {
-	logger   *zap.Logger
+	logger   Logger
 	protocol string
 	address  string
 	port     string
 	timeout  time.Duration
+	metrics  *clientMetrics
+
+	metricsRegisterer promclient.Registerer
+
+	queryTimeout         time.Duration
+	followStepSuggestion bool
+	postQueries          bool
+	retryAttempts        int
+	retryBackoff         time.Duration
+
+	httpClient     *http.Client
+	faultInjection *FaultConfig
+
+	dialTimeout           time.Duration
+	tlsHandshakeTimeout   time.Duration
+	responseHeaderTimeout time.Duration
+	tlsConfig             *tls.Config
+	insecureSkipVerify    bool
+
+	bearerToken       string
+	bearerTokenFile   string
+	basicAuthUsername string
+	basicAuthPassword string
+
+	hostHeader string
+	accept     string
+	orgID      string
+	pathPrefix string
+	apiPrefix  string
+	strict     bool
+
+	compression     Compression
+	maxResultSeries int
+	queryStats      bool
+	rangeCache      *rangeCache
+	maxURLLength    int32
+
+	scrapeIntervalMillis int32
+
+	responseTransformer func([]byte) ([]byte, error)
+	dataKeyPath         []string
+	paramNames          map[string]string
+}
+
+// NewClientFromParsedURL creates new Client instance from already parsed server URL,
+// URL path (e.g. "/prometheus" behind reverse proxy) is used as path prefix
+// param: u    - server URL with scheme and host, port defaults to well-known port of the scheme
+// param: opts - options applied after the URL, e.g. WithPathPrefix overrides URL path
+func NewClientFromParsedURL(u *url.URL, opts ...Option) (*Client, error) {
+	if u == nil {
+		return nil, errors.Errorf("%v: URL is nil", funcInfo())
+	}
+	if u.Scheme == "" {
+		return nil, errors.Errorf("%v: URL %v has no scheme", funcInfo(), u)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.Errorf("%v: URL %v has no host", funcInfo(), u)
+	}
+
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		default:
+			return nil, errors.Errorf("%v: URL %v has no port", funcInfo(), u)
+		}
+	}
+
+	address := u.Hostname()
+	if strings.Contains(address, ":") {
+		address = "[" + address + "]"
+	}
+
+	opts = append([]Option{WithPathPrefix(u.Path)}, opts...)
+
+	return NewClient(u.Scheme, address, port, opts...), nil
 }
 
-// NewClient creates new Client instance
+// NewClient creates new Client instance, logging is disabled unless logger is set by option
 func NewClient(protocol, address, port string, opts ...Option) *Client {
 	client := &Client{
 		protocol: protocol,
 		address:  address,
 		port:     port,
-		logger:   zap.NewExample(),
+		logger:   NewZapLogger(zap.NewNop()),
+		timeout:  DefaultTimeout,
 	}
 
 	for _, opt := range opts {
 		opt(client)
 	}
 
+	// registered once options are applied, so failures are logged by logger set after WithMetrics
+	if client.metricsRegisterer != nil {
+		client.metrics = newClientMetrics(client.metricsRegisterer, client.logger)
+	}
+
+	if client.httpClient == nil {
+		client.httpClient = &http.Client{Timeout: client.timeout, Transport: client.transport()}
+	}
+
 	return client
 }
 
@@ -62,17 +372,83 @@ func NewClient(protocol, address, port string, opts ...Option) *Client {
 // result: []byte - contains JSON marshalled type *json.RawMessage
 // result: string - contains parsed 'resultType' field from response
 func (m *Client) QueryRequest(query string) ([]byte, string, error) {
-	prometheusRequest := fmt.Sprintf("%v://%v:%v/api/v1/query?query=%v",
-		m.protocol, m.address, m.port, query)
+	return m.QueryRequestWithContext(context.Background(), query)
+}
 
-	m.logger.Debug("Prometheus request", zap.String("query", prometheusRequest))
+// QueryRequestWithContext Prometheus query returns scalar value, request is aborted once ctx is done
+// param: ctx   - context bounding the request, cancellation error is wrapped in returned error
+// param: query - Prometheus query string
+// result: []byte - contains JSON marshalled type *json.RawMessage
+// result: string - contains parsed 'resultType' field from response
+func (m *Client) QueryRequestWithContext(ctx context.Context, query string) ([]byte, string, error) {
+	prometheusRequest := m.queryURL(query)
 
-	resp, resultType, err := m.query(prometheusRequest)
+	m.logger.Debug("Prometheus request", "query", prometheusRequest)
+
+	result, err := m.queryResult(ctx, prometheusRequest)
 	if err != nil {
 		return nil, "", errors.Wrapf(err, "%v: reading response body failed", funcInfo())
 	}
 
-	return resp, resultType, nil
+	return result.Result, result.ResultType, nil
+}
+
+// QueryResult Prometheus query result together with details of its evaluation
+type QueryResult struct {
+	// Result contains JSON marshalled type *json.RawMessage
+	Result []byte
+	// ResultType contains parsed 'resultType' field from response
+	ResultType string
+	// EvalTime evaluation timestamp the query was sent with
+	EvalTime time.Time
+	// Warnings contains 'warnings' field from response, e.g. partial data or truncation
+	Warnings []string
+	// Stats contains sample statistics of evaluation, nil unless WithQueryStats is enabled
+	Stats *QueryStats
+}
+
+// truncationWarnings substrings of warnings servers emit when they truncate results
+var truncationWarnings = []string{
+	"truncated",
+	"hit the max number of series",
+}
+
+// WasTruncated reports whether server warned the result was truncated, e.g. due to limit
+func (r *QueryResult) WasTruncated() bool {
+	for _, warning := range r.Warnings {
+		warning = strings.ToLower(warning)
+		for _, truncation := range truncationWarnings {
+			if strings.Contains(warning, truncation) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// QueryAt Prometheus query evaluated at given time
+// param: query    - Prometheus query string
+// param: evalTime - evaluation timestamp, zero time means current time captured when the request is built
+// result: *QueryResult - result with the evaluation timestamp used, so the query can be reproduced
+func (m *Client) QueryAt(query string, evalTime time.Time) (*QueryResult, error) {
+	if evalTime.IsZero() {
+		evalTime = time.Now()
+	}
+
+	params := m.queryParams(query)
+	params.Set("time", formatTime(evalTime))
+	prometheusRequest := m.apiURL("query", params)
+
+	m.logger.Debug("Prometheus request", "query", prometheusRequest)
+
+	result, err := m.queryResult(context.Background(), prometheusRequest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: reading response body failed", funcInfo())
+	}
+	result.EvalTime = evalTime
+
+	return result, nil
 }
 
 // QueryRangeRequest Prometheus query range returns matrix values
@@ -83,40 +459,583 @@ func (m *Client) QueryRequest(query string) ([]byte, string, error) {
 // result: []byte - contains JSON marshalled type *json.RawMessage
 // result: string - contains parsed 'resultType' field from response
 func (m *Client) QueryRangeRequest(query string, start, end time.Time, step time.Duration) ([]byte, string, error) {
-	prometheusRequest := fmt.Sprintf("%v://%v:%v/api/v1/query_range?query=%v&start=%v&end=%v&step=%v",
-		m.protocol, m.address, m.port, query, start.Format(time.RFC3339Nano), end.Format(time.RFC3339Nano), shortDur(step))
+	return m.QueryRangeRequestWithContext(context.Background(), query, start, end, step)
+}
 
-	m.logger.Debug("Prometheus request", zap.String("query", prometheusRequest))
+// QueryRangeRequestWithContext Prometheus query range returns matrix values, request is aborted once ctx is done
+// param: ctx   - context bounding the request, cancellation error is wrapped in returned error
+// param: query - Prometheus query string
+// param: start - start time of range interval
+// param: end   - end time of range interval
+// param: step  - sampling interval
+// result: []byte - contains JSON marshalled type *json.RawMessage
+// result: string - contains parsed 'resultType' field from response
+func (m *Client) QueryRangeRequestWithContext(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]byte, string, error) {
+	result, err := m.queryRangeStep(ctx, query, start, end, step)
+	if err != nil {
+		return nil, "", err
+	}
 
-	resp, resultType, err := m.query(prometheusRequest)
+	return result.Result, result.ResultType, nil
+}
+
+// queryRangeStep checks step and runs range query, retrying with step suggested by server if enabled
+// by WithFollowStepSuggestion
+func (m *Client) queryRangeStep(ctx context.Context, query string, start, end time.Time, step time.Duration) (*QueryResult, error) {
+	if err := m.checkStep(start, end, step); err != nil {
+		return nil, errors.Wrapf(err, "%v: step check failed", funcInfo())
+	}
+
+	result, err := m.queryRangeResult(ctx, query, start, end, shortDur(step))
+	if err != nil && m.followStepSuggestion {
+		if suggested, ok := suggestedStep(err, start, end); ok && suggested > step {
+			m.logger.Warn("Prometheus range query rejected, retrying with suggested step",
+				"step", step, "suggestedStep", suggested)
+			return m.queryRangeResult(ctx, query, start, end, shortDur(suggested))
+		}
+	}
+
+	return result, err
+}
+
+// QueryWithWarnings Prometheus query returning also warnings server sent with result,
+// e.g. partial response warnings of Thanos when some store was unavailable
+// param: query - Prometheus query string
+// result: []byte - contains JSON marshalled type *json.RawMessage
+// result: string - contains parsed 'resultType' field from response
+// result: []string - contains 'warnings' field from response, nil when result is complete
+func (m *Client) QueryWithWarnings(query string) ([]byte, string, []string, error) {
+	prometheusRequest := m.queryURL(query)
+
+	m.logger.Debug("Prometheus request", "query", prometheusRequest)
+
+	result, err := m.queryResult(context.Background(), prometheusRequest)
 	if err != nil {
-		return nil, "", errors.Wrapf(err, "%v: reading response body failed", funcInfo())
+		return nil, "", nil, errors.Wrapf(err, "%v: reading response body failed", funcInfo())
 	}
 
-	return resp, resultType, nil
+	return result.Result, result.ResultType, result.Warnings, nil
+}
+
+// QueryRangeWithWarnings Prometheus query range returning also warnings server sent with result,
+// e.g. partial response warnings of Thanos when some store was unavailable
+// param: query - Prometheus query string
+// param: start - start time of range interval
+// param: end   - end time of range interval
+// param: step  - sampling interval
+// result: []byte - contains JSON marshalled type *json.RawMessage
+// result: string - contains parsed 'resultType' field from response
+// result: []string - contains 'warnings' field from response, nil when result is complete
+func (m *Client) QueryRangeWithWarnings(query string, start, end time.Time, step time.Duration) ([]byte, string, []string, error) {
+	result, err := m.queryRangeStep(context.Background(), query, start, end, step)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return result.Result, result.ResultType, result.Warnings, nil
+}
+
+// QueryRangeRawStep Prometheus query range with step passed through exactly as given
+// param: query - Prometheus query string
+// param: start - start time of range interval
+// param: end   - end time of range interval
+// param: step  - sampling interval as Prometheus duration ("15s", "1m") or float number of seconds ("30")
+// result: []byte - contains JSON marshalled type *json.RawMessage
+// result: string - contains parsed 'resultType' field from response
+func (m *Client) QueryRangeRawStep(query string, start, end time.Time, step string) ([]byte, string, error) {
+	if !validStep(step) {
+		return nil, "", errors.Errorf("%v: Invalid step %q", funcInfo(), step)
+	}
+
+	return m.queryRange(context.Background(), query, start, end, step)
+}
+
+// QueryRangeRequestStepSeconds Prometheus query range with step given as number of seconds
+// param: query       - Prometheus query string
+// param: start       - start time of range interval
+// param: end         - end time of range interval
+// param: stepSeconds - positive sampling interval in seconds, sent as plain number, e.g. "15" or "0.5"
+// result: []byte - contains JSON marshalled type *json.RawMessage
+// result: string - contains parsed 'resultType' field from response
+func (m *Client) QueryRangeRequestStepSeconds(query string, start, end time.Time, stepSeconds float64) ([]byte, string, error) {
+	if !(stepSeconds > 0) || math.IsInf(stepSeconds, 1) {
+		return nil, "", errors.Errorf("%v: Invalid step %v", funcInfo(), stepSeconds)
+	}
+
+	if err := m.checkStep(start, end, time.Duration(stepSeconds*float64(time.Second))); err != nil {
+		return nil, "", errors.Wrapf(err, "%v: step check failed", funcInfo())
+	}
+
+	return m.queryRange(context.Background(), query, start, end, strconv.FormatFloat(stepSeconds, 'f', -1, 64))
+}
+
+// checkStep warns, or fails in strict mode, when step is not smaller than range window
+// as such query returns at most single point per series, which is usually a bug
+func (m *Client) checkStep(start, end time.Time, step time.Duration) error {
+	window := end.Sub(start)
+	if step < window {
+		return nil
+	}
+
+	if m.strict {
+		return errors.Errorf("%v: Step %v is not smaller than range window %v", funcInfo(), step, window)
+	}
+
+	m.logger.Warn("Prometheus range query step is not smaller than range window",
+		"step", step, "window", window)
+
+	return nil
+}
+
+// BuildQueryRangeURL returns URL QueryRangeRequest would request, without executing it
+// param: query - Prometheus query string
+// param: start - start time of range interval
+// param: end   - end time of range interval
+// param: step  - sampling interval
+// result: string - query_range URL, e.g. for "view in Prometheus" links
+func (m *Client) BuildQueryRangeURL(query string, start, end time.Time, step time.Duration) (string, error) {
+	if err := m.checkStep(start, end, step); err != nil {
+		return "", errors.Wrapf(err, "%v: step check failed", funcInfo())
+	}
+
+	return m.queryRangeURL(query, start, end, shortDur(step)), nil
+}
+
+func (m *Client) queryRangeURL(query string, start, end time.Time, step string) string {
+	params := m.queryParams(query)
+	params.Set(m.paramName(ParamStart), formatTime(start))
+	params.Set(m.paramName(ParamEnd), formatTime(end))
+	params.Set(m.paramName(ParamStep), step)
+
+	return m.apiURL("query_range", params)
+}
+
+func (m *Client) queryURL(query string) string {
+	return m.apiURL("query", m.queryParams(query))
+}
+
+// queryParams returns params with query expression and server-side timeout if set,
+// encoded once URL is built so any PromQL is sent intact
+func (m *Client) queryParams(query string) url.Values {
+	params := url.Values{m.paramName(ParamQuery): {query}}
+	if m.queryTimeout > 0 {
+		params.Set("timeout", shortDur(m.queryTimeout))
+	}
+
+	return params
+}
+
+// paramName returns name query param is sent with, standard name unless overridden by WithParamName
+func (m *Client) paramName(standard string) string {
+	if name, ok := m.paramNames[standard]; ok {
+		return name
+	}
+
+	return standard
+}
+
+func (m *Client) queryRange(ctx context.Context, query string, start, end time.Time, step string) ([]byte, string, error) {
+	result, err := m.queryRangeResult(ctx, query, start, end, step)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return result.Result, result.ResultType, nil
+}
+
+// queryRangeResult runs range query with step formatted as it is sent, served from cache if enabled by WithRangeCache
+func (m *Client) queryRangeResult(ctx context.Context, query string, start, end time.Time, step string) (*QueryResult, error) {
+	var cacheKey string
+	if m.rangeCache != nil {
+		cacheKey = rangeCacheKey(query, start, end, step)
+		if result, ok := m.rangeCache.get(cacheKey); ok {
+			m.logger.Debug("Prometheus range query served from cache", "query", query)
+			return result, nil
+		}
+	}
+
+	prometheusRequest := m.queryRangeURL(query, start, end, step)
+
+	m.logger.Debug("Prometheus request", "query", prometheusRequest)
+
+	result, err := m.queryResult(ctx, prometheusRequest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: reading response body failed", funcInfo())
+	}
+
+	if m.rangeCache != nil {
+		m.rangeCache.set(cacheKey, result)
+	}
+
+	return result, nil
 }
 
 func (m *Client) query(query string) ([]byte, string, error) {
-	http.DefaultClient.Timeout = m.timeout
+	result, err := m.queryResult(context.Background(), query)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "%v: query failed", funcInfo())
+	}
+
+	return result.Result, result.ResultType, nil
+}
+
+// queryResult requests query, retrying transient failures if enabled by WithRetry
+func (m *Client) queryResult(ctx context.Context, query string) (*QueryResult, error) {
+	if m.queryStats {
+		query += "&stats=all"
+	}
 
-	resp, err := http.DefaultClient.Get(query)
+	var result *QueryResult
+
+	err := m.queryData(ctx, query, func(data []byte) error {
+		var err error
+		result, err = m.parseQueryResult(data)
+		return err
+	})
 	if err != nil {
-		return nil, "", errors.Wrapf(err, "%v: getting result from Prometheus failed", funcInfo())
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// queryData requests query and calls handle with response data, after transformation by WithResponseTransformer
+// and envelope lookup of WithDataKeyPath, retrying transient failures if enabled by WithRetry.
+// Data is valid only until handle returns.
+func (m *Client) queryData(ctx context.Context, query string, handle func(data []byte) error) error {
+	return m.retry(ctx, func() error {
+		return m.queryDataOnce(ctx, query, handle)
+	})
+}
+
+// newQueryRequest returns GET request of query URL, or POST request with URL params moved
+// into form-encoded body if enabled by WithPostQueries or URL is too long
+func (m *Client) newQueryRequest(ctx context.Context, query string) (*http.Request, error) {
+	if !m.postQueries && !m.urlTooLong(query) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, query, nil)
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	return newFormRequest(ctx, query)
+}
+
+// newFormRequest returns POST request of URL path with its params moved into form-encoded body
+func newFormRequest(ctx context.Context, rawURL string) (*http.Request, error) {
+	endpoint, params := rawURL, ""
+	if i := strings.IndexByte(rawURL, '?'); i >= 0 {
+		endpoint, params = rawURL[:i], rawURL[i+1:]
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(params))
 	if err != nil {
-		return nil, "", errors.Wrapf(err, "%v: reading response body failed", funcInfo())
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return req, nil
+}
+
+// urlTooLong reports whether URL exceeds length found by DetectMaxURLLength, or defaultMaxURLLength if not detected
+func (m *Client) urlTooLong(rawURL string) bool {
+	maxLength := int(atomic.LoadInt32(&m.maxURLLength))
+	if maxLength == 0 {
+		maxLength = defaultMaxURLLength
+	}
+
+	return len(rawURL) > maxLength
+}
+
+func (m *Client) queryDataOnce(ctx context.Context, query string, handle func(data []byte) error) error {
+	req, err := m.newQueryRequest(ctx, query)
+	if err != nil {
+		return errors.Wrapf(err, "%v: creating request failed", funcInfo())
+	}
+
+	resp, err := m.do(req)
+	if err != nil {
+		return errors.Wrapf(err, "%v: getting result from Prometheus failed", funcInfo())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.Wrapf(ErrNotSupported, "%v: %v", funcInfo(), req.URL.Path)
+	}
+
+	body, err := readBody(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "%v: reading response body failed", funcInfo())
+	}
+	defer releaseBody(body)
+
+	m.debugResponse(body.Bytes())
+
+	if err := responseStatusError(resp, body.Bytes()); err != nil {
+		return errors.Wrapf(err, "%v: request failed", funcInfo())
+	}
+
+	if body.Len() == 0 {
+		return errors.Wrapf(ErrEmptyResponse, "%v: status %v", funcInfo(), resp.StatusCode)
+	}
+
+	data := body.Bytes()
+	if m.responseTransformer != nil {
+		data, err = m.responseTransformer(data)
+		if err != nil {
+			return errors.Wrapf(err, "%v: transforming response failed", funcInfo())
+		}
+	}
+
+	data, err = m.envelope(data)
+	if err != nil {
+		return errors.Wrapf(err, "%v: response envelope lookup failed", funcInfo())
+	}
+
+	return handle(data)
+}
+
+// parseQueryResult parses result, warnings and stats if enabled by WithQueryStats of query response data
+func (m *Client) parseQueryResult(data []byte) (*QueryResult, error) {
+	response, resultType, err := m.parseResponse(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: parsing response failed", funcInfo())
+	}
+
+	warnings, err := parseWarnings(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: parsing warnings failed", funcInfo())
+	}
+
+	result := &QueryResult{Result: response, ResultType: resultType, Warnings: warnings}
+
+	if m.queryStats {
+		result.Stats, err = parseStats(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%v: parsing stats failed", funcInfo())
+		}
 	}
 
-	m.logger.Debug("Prometheus response", zap.String("result", string(body)))
+	return result, nil
+}
+
+func (m *Client) baseURL() string {
+	return fmt.Sprintf("%v://%v:%v", m.protocol, m.address, m.port)
+}
+
+// rootURL returns base URL with configured path prefix, identifying Prometheus server behind shared host
+func (m *Client) rootURL() string {
+	return m.baseURL() + strings.TrimSuffix(m.serverPath(), "/")
+}
+
+// serverPath joins path elements under configured path prefix, normalizing slashes
+func (m *Client) serverPath(elems ...string) string {
+	return path.Join(append([]string{"/", m.pathPrefix}, elems...)...)
+}
 
-	response, resultType, err := m.parseResponse(body)
+// apiPath returns path of Prometheus HTTP API endpoint
+func (m *Client) apiPath(endpoint string) string {
+	apiPrefix := m.apiPrefix
+	if apiPrefix == "" {
+		apiPrefix = DefaultAPIPrefix
+	}
+
+	return m.serverPath(apiPrefix, endpoint)
+}
+
+// apiURL returns URL of Prometheus HTTP API endpoint with encoded params
+func (m *Client) apiURL(endpoint string, params url.Values) string {
+	apiURL := m.baseURL() + m.apiPath(endpoint)
+	if len(params) > 0 {
+		apiURL += "?" + params.Encode()
+	}
+
+	return apiURL
+}
+
+// get requests Prometheus HTTP API endpoint, caller is responsible for closing response body
+func (m *Client) get(ctx context.Context, endpoint string, params url.Values) (*http.Response, error) {
+	return m.getURL(ctx, m.apiURL(endpoint, params))
+}
+
+// getOrPost requests Prometheus HTTP API endpoint, params are sent in form-encoded POST body
+// when URL would be too long, caller is responsible for closing response body
+func (m *Client) getOrPost(ctx context.Context, endpoint string, params url.Values) (*http.Response, error) {
+	rawURL := m.apiURL(endpoint, params)
+	if !m.urlTooLong(rawURL) {
+		return m.getURL(ctx, rawURL)
+	}
+
+	m.logger.Debug("Prometheus request sent as POST", "endpoint", endpoint, "length", len(rawURL))
+
+	req, err := newFormRequest(ctx, rawURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: creating request failed", funcInfo())
+	}
+
+	resp, err := m.do(req)
 	if err != nil {
-		return nil, "", errors.Wrapf(err, "%v: parsing response failed", funcInfo())
+		return nil, errors.Wrapf(err, "%v: getting result from Prometheus failed", funcInfo())
 	}
 
-	return response, resultType, nil
+	return resp, nil
+}
+
+// getURL requests given URL, caller is responsible for closing response body
+func (m *Client) getURL(ctx context.Context, rawURL string) (*http.Response, error) {
+	return m.getURLAccepting(ctx, rawURL, "")
+}
+
+// getURLAccepting requests given URL accepting given media type instead of configured one,
+// e.g. text exposition format, caller is responsible for closing response body
+func (m *Client) getURLAccepting(ctx context.Context, rawURL, accept string) (*http.Response, error) {
+	m.logger.Debug("Prometheus request", "query", rawURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: creating request failed", funcInfo())
+	}
+
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := m.do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: getting result from Prometheus failed", funcInfo())
+	}
+
+	return resp, nil
+}
+
+// getData requests Prometheus HTTP API endpoint and returns raw 'data' field of response
+func (m *Client) getData(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	resp, err := m.get(ctx, endpoint, params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: request failed", funcInfo())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.Wrapf(ErrNotSupported, "%v: %v", funcInfo(), endpoint)
+	}
+
+	body, err := readBody(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: reading response body failed", funcInfo())
+	}
+	defer releaseBody(body)
+
+	m.debugResponse(body.Bytes())
+
+	if err := responseStatusError(resp, body.Bytes()); err != nil {
+		return nil, errors.Wrapf(err, "%v: request failed", funcInfo())
+	}
+
+	if body.Len() == 0 {
+		return nil, errors.Wrapf(ErrEmptyResponse, "%v: status %v", funcInfo(), resp.StatusCode)
+	}
+
+	data, err := m.envelope(body.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: response envelope lookup failed", funcInfo())
+	}
+
+	// json.RawMessage holds copy of the data, so returned slice does not alias pooled buffer
+	var objmap map[string]*json.RawMessage
+
+	err = json.Unmarshal(data, &objmap)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: response unmarshal failed", funcInfo())
+	}
+
+	if err := parseAPIError(data); err != nil {
+		return nil, errors.Wrapf(err, "%v: %v request failed", funcInfo(), endpoint)
+	}
+
+	dataObj, ok := objmap["data"]
+	if !ok || dataObj == nil {
+		return nil, errors.Errorf("%v: Data parsing failed", funcInfo())
+	}
+
+	return []byte(*dataObj), nil
+}
+
+func (m *Client) do(req *http.Request) (*http.Response, error) {
+	if m.hostHeader != "" {
+		req.Host = m.hostHeader
+	}
+
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", m.acceptHeader())
+	}
+
+	if m.compression != "" {
+		req.Header.Set("Accept-Encoding", string(m.compression))
+	}
+
+	if m.orgID != "" {
+		req.Header.Set(orgIDHeader, m.orgID)
+	}
+
+	if err := m.setAuthorization(req); err != nil {
+		return nil, errors.Wrapf(err, "%v: setting authorization failed", funcInfo())
+	}
+
+	start := time.Now()
+
+	resp, err := m.client().Do(req)
+	if m.metrics != nil {
+		m.metrics.observe(m.endpointLabel(req.URL.Path), resp, err, time.Since(start))
+	}
+	if err != nil {
+		return nil, requestError(req.Context(), err)
+	}
+
+	if m.compression != "" {
+		if err := decompressedBody(resp); err != nil {
+			resp.Body.Close()
+			return nil, errors.Wrapf(err, "%v: response decompression failed", funcInfo())
+		}
+	}
+
+	return resp, nil
+}
+
+// acceptHeader returns media type requests accept unless request sets its own
+func (m *Client) acceptHeader() string {
+	if m.accept != "" {
+		return m.accept
+	}
+
+	return DefaultAccept
+}
+
+// client returns HTTP client requests are sent with, Client built without NewClient gets one with its timeout
+func (m *Client) client() *http.Client {
+	client := m.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: m.timeout}
+	}
+
+	if m.faultInjection != nil {
+		injected := *client
+		injected.Transport = NewFaultInjector(client.Transport, *m.faultInjection)
+		return &injected
+	}
+
+	return client
+}
+
+// requestError makes client timeout distinguishable from cancellation: timeout matches
+// context.DeadlineExceeded with errors.Is on every Go version, cancellation keeps matching context.Canceled
+func requestError(ctx context.Context, err error) error {
+	if ctx.Err() == context.Canceled || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return errors.Wrapf(context.DeadlineExceeded, "%v: %v", funcInfo(), err)
+	}
+
+	return err
 }
 
 func (m *Client) parseResponse(data []byte) ([]byte, string, error) {
@@ -130,6 +1049,10 @@ func (m *Client) parseResponse(data []byte) ([]byte, string, error) {
 		return nil, "", errors.Wrapf(err, "%v: response unmarshal failed", funcInfo())
 	}
 
+	if err := parseAPIError(data); err != nil {
+		return nil, "", errors.Wrapf(err, "%v: query failed", funcInfo())
+	}
+
 	dataObj, ok := objmap["data"]
 	if !ok {
 		return nil, "", errors.Errorf("%v: Data parsing failed", funcInfo())
@@ -144,10 +1067,6 @@ func (m *Client) parseResponse(data []byte) ([]byte, string, error) {
 	if !ok {
 		return nil, "", errors.Errorf("%v: Result parsing failed", funcInfo())
 	}
-	err = json.Unmarshal([]byte(*resultObj), &objlist)
-	if err != nil {
-		return nil, "", errors.Wrapf(err, "%v: result unmarshal failed", funcInfo())
-	}
 
 	resultTypeObj, ok := objmap["resultType"]
 	if !ok {
@@ -158,6 +1077,31 @@ func (m *Client) parseResponse(data []byte) ([]byte, string, error) {
 		return nil, "", errors.Wrapf(err, "%v: result type unmarshal failed", funcInfo())
 	}
 
+	// result of type unknown to this client is passed through as is, so callers can still inspect it
+	if !knownResultType(resultType) {
+		m.logger.Warn("Prometheus returned unknown result type", "resultType", resultType)
+		return []byte(*resultObj), ResultTypeUnknown, nil
+	}
+
+	// scalar and string results are single [<unix_time>, "<value>"] pair rather than array of series
+	if resultType == ResultTypeScalar || resultType == ResultTypeString {
+		var pair []*json.RawMessage
+		err = json.Unmarshal([]byte(*resultObj), &pair)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "%v: %v result unmarshal failed", funcInfo(), resultType)
+		}
+		if len(pair) != 2 {
+			return nil, "", errors.Errorf("%v: %v result has %v elements", funcInfo(), resultType, len(pair))
+		}
+
+		return []byte(*resultObj), resultType, nil
+	}
+
+	err = json.Unmarshal([]byte(*resultObj), &objlist)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "%v: result unmarshal failed", funcInfo())
+	}
+
 	if len(objlist) == 0 {
 		return nil, "", errors.Errorf("%v: Result is empty", funcInfo())
 	}
@@ -170,15 +1114,159 @@ func (m *Client) parseResponse(data []byte) ([]byte, string, error) {
 	return resp, resultType, nil
 }
 
+const (
+	// timeFormat time param format for whole seconds, without fractional part
+	timeFormat = "2006-01-02T15:04:05Z07:00"
+	// timeFormatMillis time param format with fixed millisecond precision Prometheus stores samples with
+	timeFormatMillis = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// formatTime formats time param in UTC, Prometheus evaluates in UTC regardless of input location.
+// Fractional seconds are truncated to fixed millisecond precision and omitted once zero,
+// as variable precision of time.RFC3339Nano is rejected by some strict proxies.
+func formatTime(t time.Time) string {
+	t = t.UTC().Truncate(time.Millisecond)
+	if t.Nanosecond() == 0 {
+		return t.Format(timeFormat)
+	}
+
+	return t.Format(timeFormatMillis)
+}
+
+// promDurationRegexp matches Prometheus duration such as 1h30m or 500ms
+var promDurationRegexp = regexp.MustCompile(`^(\d+y)?(\d+w)?(\d+d)?(\d+h)?(\d+m)?(\d+s)?(\d+ms)?$`)
+
+// validStep reports whether step is legal Prometheus duration or positive float number of seconds
+func validStep(step string) bool {
+	if step == "" {
+		return false
+	}
+
+	if promDurationRegexp.MatchString(step) {
+		return true
+	}
+
+	seconds, err := strconv.ParseFloat(step, 64)
+	return err == nil && seconds > 0 && !math.IsInf(seconds, 0)
+}
+
+// envelope returns standard Prometheus response nested under keys set by WithDataKeyPath,
+// response itself when no path is set
+func (m *Client) envelope(data []byte) ([]byte, error) {
+	for _, key := range m.dataKeyPath {
+		var objmap map[string]json.RawMessage
+
+		err := json.Unmarshal(data, &objmap)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%v: envelope unmarshal failed", funcInfo())
+		}
+
+		nested, ok := objmap[key]
+		if !ok {
+			return nil, errors.Errorf("%v: Envelope key %q not found", funcInfo(), key)
+		}
+		data = nested
+	}
+
+	return data, nil
+}
+
+// responseStatusError returns nil for 2xx response, otherwise *APIError when body reports
+// Prometheus error or *StatusError with status code and snippet of body
+func responseStatusError(resp *http.Response, body []byte) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	if err := parseAPIError(body); err != nil {
+		if apiErr, ok := err.(*APIError); ok {
+			apiErr.StatusCode = resp.StatusCode
+			return apiErr
+		}
+	}
+
+	if len(body) > maxErrorBodySnippet {
+		body = body[:maxErrorBodySnippet]
+	}
+
+	return &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+}
+
+// parseAPIError returns *APIError when response reports status "error", nil otherwise
+func parseAPIError(data []byte) error {
+	var response struct {
+		Status    string `json:"status"`
+		ErrorType string `json:"errorType"`
+		Error     string `json:"error"`
+	}
+
+	err := json.Unmarshal(data, &response)
+	if err != nil {
+		return errors.Wrapf(err, "%v: status unmarshal failed", funcInfo())
+	}
+
+	if response.Status != statusErrorResponse {
+		return nil
+	}
+
+	return &APIError{Type: response.ErrorType, Message: response.Error}
+}
+
+// parseWarnings returns optional top-level 'warnings' field of response
+func parseWarnings(data []byte) ([]string, error) {
+	var response struct {
+		Warnings []string `json:"warnings"`
+	}
+
+	err := json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: warnings unmarshal failed", funcInfo())
+	}
+
+	return response.Warnings, nil
+}
+
+// shortDurUnits Prometheus duration units shortDur composes durations of, largest first
+var shortDurUnits = []struct {
+	unit time.Duration
+	name string
+}{
+	{time.Hour, "h"},
+	{time.Minute, "m"},
+	{time.Second, "s"},
+	{time.Millisecond, "ms"},
+}
+
+// shortDur formats d as Prometheus duration, e.g. "2d", "36h", "1m30s" or "500ms". Days are used only
+// when d is whole number of days, durations with sub-millisecond part fall back to float seconds.
 func shortDur(d time.Duration) string {
-	s := d.String()
-	if strings.HasSuffix(s, "m0s") {
-		s = s[:len(s)-2]
+	if d == 0 {
+		return "0s"
 	}
-	if strings.HasSuffix(s, "h0m") {
-		s = s[:len(s)-2]
+	if d%time.Millisecond != 0 {
+		return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
 	}
-	return s
+
+	var s strings.Builder
+	if d < 0 {
+		s.WriteString("-")
+		d = -d
+	}
+
+	const day = 24 * time.Hour
+	if d%day == 0 {
+		s.WriteString(strconv.FormatInt(int64(d/day), 10) + "d")
+		return s.String()
+	}
+
+	for _, u := range shortDurUnits {
+		if n := d / u.unit; n > 0 {
+			s.WriteString(strconv.FormatInt(int64(n), 10) + u.name)
+			d -= n * u.unit
+		}
+	}
+
+	return s.String()
 }
 
 func funcInfo() string {
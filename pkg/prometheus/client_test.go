@@ -2,9 +2,16 @@ package prometheus
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
 	"reflect"
 	"strconv"
 	"strings"
@@ -12,14 +19,17 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 )
 
 var (
 	unicornResponse      = []byte(`{"data":{"resultType":"vector","result":[{"value":[1.1, "1"]}]}}`)
 	dataFailResponse     = []byte("{}")
 	resultFailResponse   = []byte(`{"data":{}}`)
+	errorStatusResponse  = []byte(`{"status":"error","errorType":"bad_data","error":"1:5: parse error: unexpected \"}\""}`)
 	resultFailResponse2  = []byte(`{"data":[]}`)
 	resultFailResponse3  = []byte("{\"data\":{\"result\":{}}}")
 	resultFailResponse4  = []byte("{\"data\":{\"result\":[],\"resultType\":\"\"}}")
@@ -44,100 +54,1418 @@ func startHTTPServer(path, port string, handler func(w http.ResponseWriter, r *h
 
 	srv := &http.Server{Addr: ":" + port, Handler: router}
 
+	// previous test server may still be releasing the port
+	listener, err := net.Listen("tcp", srv.Addr)
+	for i := 0; err != nil && i < 100; i++ {
+		time.Sleep(time.Millisecond * 10)
+		listener, err = net.Listen("tcp", srv.Addr)
+	}
+	if err != nil {
+		log.Fatalf("Listen(): %s", err)
+	}
+
 	go func() {
-		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
-			log.Fatalf("ListenAndServe(): %s", err)
+		if err := srv.Serve(listener); err != http.ErrServerClosed {
+			log.Fatalf("Serve(): %s", err)
 		}
 	}()
 
-	return srv
+	return srv
+}
+
+func TestNewClient(t *testing.T) {
+	logger := zap.NewExample()
+	customHTTPClient := &http.Client{Timeout: time.Second}
+	type args struct {
+		protocol string
+		address  string
+		port     string
+		opts     []Option
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Client
+	}{
+		{
+			name: "Test NewClient unicorn path",
+			args: args{protocol: "http", address: "127.0.0.1", port: "9090", opts: []Option{WithLogger(logger), WithTimeout(time.Second * 30)}},
+			want: &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30, httpClient: &http.Client{Timeout: time.Second * 30}},
+		},
+		{
+			name: "Test NewClient default timeout",
+			args: args{protocol: "http", address: "127.0.0.1", port: "9090", opts: []Option{WithLogger(logger)}},
+			want: &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: DefaultTimeout, httpClient: &http.Client{Timeout: DefaultTimeout}},
+		},
+		{
+			name: "Test NewClient timeout disabled",
+			args: args{protocol: "http", address: "127.0.0.1", port: "9090", opts: []Option{WithLogger(logger), WithTimeout(0)}},
+			want: &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), httpClient: &http.Client{}},
+		},
+		{
+			name: "Test NewClient custom HTTP client",
+			args: args{protocol: "http", address: "127.0.0.1", port: "9090", opts: []Option{WithLogger(logger), WithHTTPClient(customHTTPClient)}},
+			want: &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: DefaultTimeout, httpClient: customHTTPClient},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewClient(tt.args.protocol, tt.args.address, tt.args.port, tt.args.opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewClient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClientFromParsedURL(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name           string
+		rawURL         string
+		wantBaseURL    string
+		wantPathPrefix string
+		wantErr        bool
+	}{
+		{
+			name:           "Test NewClientFromParsedURL path prefix",
+			rawURL:         "http://127.0.0.1:9090/prometheus",
+			wantBaseURL:    "http://127.0.0.1:9090",
+			wantPathPrefix: "/prometheus",
+		},
+		{
+			name:        "Test NewClientFromParsedURL default https port",
+			rawURL:      "https://prometheus.example.com",
+			wantBaseURL: "https://prometheus.example.com:443",
+		},
+		{
+			name:        "Test NewClientFromParsedURL IPv6",
+			rawURL:      "http://[::1]:9090",
+			wantBaseURL: "http://[::1]:9090",
+		},
+		{
+			name:    "Test NewClientFromParsedURL no scheme",
+			rawURL:  "//127.0.0.1:9090/prometheus",
+			wantErr: true,
+		},
+		{
+			name:    "Test NewClientFromParsedURL no host",
+			rawURL:  "/prometheus",
+			wantErr: true,
+		},
+		{
+			name:    "Test NewClientFromParsedURL nil",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u *url.URL
+			if tt.rawURL != "" {
+				var err error
+				if u, err = url.Parse(tt.rawURL); err != nil {
+					t.Fatalf("url.Parse() error = %v", err)
+				}
+			}
+
+			got, err := NewClientFromParsedURL(u, WithLogger(logger))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewClientFromParsedURL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.baseURL() != tt.wantBaseURL || got.pathPrefix != tt.wantPathPrefix {
+				t.Errorf("NewClientFromParsedURL() base URL = %v, path prefix = %v, want %v, %v",
+					got.baseURL(), got.pathPrefix, tt.wantBaseURL, tt.wantPathPrefix)
+			}
+		})
+	}
+
+	u, _ := url.Parse("http://127.0.0.1:9090/prometheus/")
+	m, err := NewClientFromParsedURL(u, WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewClientFromParsedURL() error = %v", err)
+	}
+
+	var gotPath string
+	httpServer := startHTTPServer("/{path:.*}", "9090", func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		unicornHandler(w, r)
+	})
+	defer httpServer.Shutdown(context.Background())
+
+	if _, _, err := m.QueryRequest("QUERY"); err != nil {
+		t.Fatalf("Client.QueryRequest() error = %v", err)
+	}
+	if gotPath != "/prometheus/api/v1/query" {
+		t.Errorf("Client.QueryRequest() path = %v, want /prometheus/api/v1/query", gotPath)
+	}
+}
+
+func TestNewClient_silentByDefault(t *testing.T) {
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = writer
+
+	httpServer := startHTTPServer("/api/v1/query", "9090", unicornHandler)
+	m := NewClient("http", "127.0.0.1", "9090")
+	_, _, queryErr := m.QueryRequest("QUERY")
+	httpServer.Shutdown(context.Background())
+
+	os.Stdout = stdout
+	writer.Close()
+	output, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if queryErr != nil {
+		t.Fatalf("Client.QueryRequest() error = %v", queryErr)
+	}
+	if len(output) != 0 {
+		t.Errorf("NewClient() default logger output = %s, want none", output)
+	}
+}
+
+// countingTransport counts requests passed to the default transport
+type countingTransport struct {
+	requests int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	transport := &countingTransport{}
+	m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithHTTPClient(&http.Client{Transport: transport}))
+
+	httpServer := startHTTPServer("/api/v1/query", "9090", unicornHandler)
+	defer httpServer.Shutdown(context.Background())
+
+	if _, _, err := m.QueryRequest("QUERY"); err != nil {
+		t.Fatalf("Client.QueryRequest() error = %v", err)
+	}
+	if transport.requests != 1 {
+		t.Errorf("WithHTTPClient() transport requests = %v, want 1", transport.requests)
+	}
+}
+
+// closeTrackingTransport counts response bodies returned and closed
+type closeTrackingTransport struct {
+	opened int
+	closed int
+}
+
+func (c *closeTrackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	c.opened++
+	resp.Body = &trackingBody{ReadCloser: resp.Body, closed: &c.closed}
+	return resp, nil
+}
+
+type trackingBody struct {
+	io.ReadCloser
+	closed *int
+}
+
+func (b *trackingBody) Close() error {
+	*b.closed++
+	return b.ReadCloser.Close()
+}
+
+func TestClient_query_closesBody(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, r *http.Request)
+		wantErr bool
+	}{
+		{name: "Test query closes body unicorn path", handler: unicornHandler},
+		{name: "Test query closes body data fail", handler: dataFailhandler, wantErr: true},
+		{name: "Test query closes body empty body", handler: responseHandler([]byte{}), wantErr: true},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query", "9090", tt.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &closeTrackingTransport{}
+			m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithHTTPClient(&http.Client{Transport: transport}))
+
+			for i := 0; i < 3; i++ {
+				if _, _, err := m.QueryRequest("QUERY"); (err != nil) != tt.wantErr {
+					t.Errorf("Client.QueryRequest() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+			if transport.opened != 3 || transport.closed != transport.opened {
+				t.Errorf("response bodies opened = %v, closed = %v", transport.opened, transport.closed)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_timeoutIsolation(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	defaultTimeout := http.DefaultClient.Timeout
+
+	short := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithTimeout(time.Millisecond))
+	long := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithTimeout(time.Second*30))
+
+	httpServer := startHTTPServer("/api/v1/query", "9090", timeoutHandler)
+	defer httpServer.Shutdown(context.Background())
+
+	if _, _, err := short.QueryRequest("QUERY"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("short Client.QueryRequest() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if _, _, err := long.QueryRequest("QUERY"); errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("long Client.QueryRequest() error = %v, must not time out", err)
+	}
+	if http.DefaultClient.Timeout != defaultTimeout {
+		t.Errorf("http.DefaultClient.Timeout = %v, want %v", http.DefaultClient.Timeout, defaultTimeout)
+	}
+}
+
+func TestWithHostHeader(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name string
+		m    *Client
+		want string
+	}{
+		{
+			name: "Test WithHostHeader overridden",
+			m:    NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithTimeout(time.Second*30), WithHostHeader("prometheus.example.com")),
+			want: "prometheus.example.com",
+		},
+		{
+			name: "Test WithHostHeader default",
+			m:    NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithTimeout(time.Second*30)),
+			want: "127.0.0.1:9090",
+		},
+	}
+	for _, tt := range tests {
+		var got string
+		httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+			got = r.Host
+			unicornHandler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.m.QueryRequest("QUERY"); err != nil {
+				t.Errorf("Client.QueryRequest() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Host = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestWithAccept(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name string
+		m    *Client
+		want string
+	}{
+		{
+			name: "Test WithAccept default",
+			m:    NewClient("http", "127.0.0.1", "9090", WithLogger(logger)),
+			want: "application/json",
+		},
+		{
+			name: "Test WithAccept overridden",
+			m:    NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithAccept("application/vnd.custom+json")),
+			want: "application/vnd.custom+json",
+		},
+	}
+	for _, tt := range tests {
+		var got string
+		httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Get("Accept")
+			unicornHandler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.m.QueryRequest("QUERY"); err != nil {
+				t.Errorf("Client.QueryRequest() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Accept = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestWithOrgIDFromEnv(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "Test WithOrgIDFromEnv set", value: "tenant-1", want: "tenant-1"},
+		{name: "Test WithOrgIDFromEnv empty", value: ""},
+	}
+	for _, tt := range tests {
+		var got string
+		httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Get("X-Scope-OrgID")
+			unicornHandler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("PROMETHEUS_CLIENT_TEST_ORG_ID", tt.value)
+			defer os.Unsetenv("PROMETHEUS_CLIENT_TEST_ORG_ID")
+
+			m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithOrgIDFromEnv("PROMETHEUS_CLIENT_TEST_ORG_ID"))
+			if _, _, err := m.QueryRequest("QUERY"); err != nil {
+				t.Errorf("Client.QueryRequest() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("X-Scope-OrgID = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestWithResponseTransformer(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	unwrap := func(data []byte) ([]byte, error) {
+		var envelope struct {
+			Payload json.RawMessage `json:"payload"`
+		}
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			return nil, err
+		}
+		return envelope.Payload, nil
+	}
+	fail := func([]byte) ([]byte, error) {
+		return nil, errors.New("transformer failed")
+	}
+
+	tests := []struct {
+		name     string
+		m        *Client
+		response string
+		want     []byte
+		want1    string
+		wantErr  bool
+	}{
+		{
+			name:     "Test WithResponseTransformer unicorn path",
+			m:        NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithResponseTransformer(unwrap)),
+			response: `{"backend":"custom","payload":` + string(unicornResponse) + `}`,
+			want:     []byte(`[{"value":[1.1,"1"]}]`),
+			want1:    "vector",
+		},
+		{
+			name:     "Test WithResponseTransformer wrapped response without transformer",
+			m:        NewClient("http", "127.0.0.1", "9090", WithLogger(logger)),
+			response: `{"backend":"custom","payload":` + string(unicornResponse) + `}`,
+			wantErr:  true,
+		},
+		{
+			name:     "Test WithResponseTransformer transformer fail",
+			m:        NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithResponseTransformer(fail)),
+			response: string(unicornResponse),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, tt.response)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.m.QueryRequest("QUERY")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.QueryRequest() got = %s, want %s", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("Client.QueryRequest() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestWithQueryTimeout(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Unix(1500000000, 0)
+
+	tests := []struct {
+		name        string
+		m           *Client
+		wantTimeout string
+	}{
+		{
+			name:        "Test WithQueryTimeout set",
+			m:           NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithQueryTimeout(90*time.Second)),
+			wantTimeout: "1m30s",
+		},
+		{
+			name:        "Test WithQueryTimeout whole minutes",
+			m:           NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithQueryTimeout(2*time.Minute)),
+			wantTimeout: "2m",
+		},
+		{
+			name: "Test WithQueryTimeout not set",
+			m:    NewClient("http", "127.0.0.1", "9090", WithLogger(logger)),
+		},
+	}
+	for _, tt := range tests {
+		gotTimeouts := map[string]string{}
+		httpServer := startHTTPServer("/api/v1/{endpoint}", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotTimeouts[mux.Vars(r)["endpoint"]] = r.URL.Query().Get("timeout")
+			fmt.Fprint(w, string(unicornResponse))
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.m.QueryRequest("QUERY"); err != nil {
+				t.Fatalf("Client.QueryRequest() error = %v", err)
+			}
+			if _, _, err := tt.m.QueryRangeRequest("QUERY", start, start.Add(time.Hour), time.Minute); err != nil {
+				t.Fatalf("Client.QueryRangeRequest() error = %v", err)
+			}
+			for _, endpoint := range []string{"query", "query_range"} {
+				if gotTimeouts[endpoint] != tt.wantTimeout {
+					t.Errorf("%v timeout param = %q, want %q", endpoint, gotTimeouts[endpoint], tt.wantTimeout)
+				}
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_QueryWithWarnings(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Unix(1500000000, 0)
+
+	tests := []struct {
+		name         string
+		response     string
+		want         []byte
+		want1        string
+		wantWarnings []string
+		wantErr      bool
+	}{
+		{
+			name:         "Test QueryWithWarnings partial response",
+			response:     `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1500000000,"1"]}]},"warnings":["store 10.0.0.1:10901 unavailable"]}`,
+			want:         []byte(`[{"metric":{},"value":[1500000000,"1"]}]`),
+			want1:        "vector",
+			wantWarnings: []string{"store 10.0.0.1:10901 unavailable"},
+		},
+		{
+			name:     "Test QueryWithWarnings complete response",
+			response: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1500000000,"1"]}]}}`,
+			want:     []byte(`[{"metric":{},"value":[1500000000,"1"]}]`),
+			want1:    "vector",
+		},
+		{
+			name:     "Test QueryWithWarnings error",
+			response: `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/{endpoint}", "9090", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, tt.response)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+
+			got, got1, gotWarnings, err := m.QueryWithWarnings("QUERY")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.QueryWithWarnings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) || got1 != tt.want1 || !reflect.DeepEqual(gotWarnings, tt.wantWarnings) {
+				t.Errorf("Client.QueryWithWarnings() = %s, %v, %v, want %s, %v, %v", got, got1, gotWarnings, tt.want, tt.want1, tt.wantWarnings)
+			}
+
+			got, got1, gotWarnings, err = m.QueryRangeWithWarnings("QUERY", start, start.Add(time.Hour), time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.QueryRangeWithWarnings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) || got1 != tt.want1 || !reflect.DeepEqual(gotWarnings, tt.wantWarnings) {
+				t.Errorf("Client.QueryRangeWithWarnings() = %s, %v, %v, want %s, %v, %v", got, got1, gotWarnings, tt.want, tt.want1, tt.wantWarnings)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestWithPostQueries(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Unix(1500000000, 0)
+	longQuery := `sum(rate(http_requests_total{path=~"` + strings.Repeat("/api/x|", 1000) + `"}[5m]))`
+
+	tests := []struct {
+		name       string
+		m          *Client
+		query      string
+		wantMethod string
+	}{
+		{
+			name:       "Test WithPostQueries set",
+			m:          NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithPostQueries()),
+			query:      longQuery,
+			wantMethod: http.MethodPost,
+		},
+		{
+			name:       "Test WithPostQueries not set",
+			m:          NewClient("http", "127.0.0.1", "9090", WithLogger(logger)),
+			query:      "QUERY",
+			wantMethod: http.MethodGet,
+		},
+		{
+			name:       "Test WithPostQueries URL over detected length",
+			m:          &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30, maxURLLength: 2048},
+			query:      longQuery,
+			wantMethod: http.MethodPost,
+		},
+		{
+			name:       "Test WithPostQueries URL under detected length",
+			m:          &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30, maxURLLength: 2048},
+			query:      "QUERY",
+			wantMethod: http.MethodGet,
+		},
+	}
+	for _, tt := range tests {
+		http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+
+		type request struct{ method, urlQuery, formQuery string }
+		got := map[string]request{}
+		httpServer := startHTTPServer("/api/v1/{endpoint}", "9090", func(w http.ResponseWriter, r *http.Request) {
+			urlQuery := r.URL.Query().Get("query")
+			got[mux.Vars(r)["endpoint"]] = request{method: r.Method, urlQuery: urlQuery, formQuery: r.PostFormValue("query")}
+			fmt.Fprint(w, string(unicornResponse))
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.m.QueryRequest(tt.query); err != nil {
+				t.Fatalf("Client.QueryRequest() error = %v", err)
+			}
+			if _, _, err := tt.m.QueryRangeRequest(tt.query, start, start.Add(time.Hour), time.Minute); err != nil {
+				t.Fatalf("Client.QueryRangeRequest() error = %v", err)
+			}
+			for _, endpoint := range []string{"query", "query_range"} {
+				req := got[endpoint]
+				if req.method != tt.wantMethod {
+					t.Errorf("%v method = %v, want %v", endpoint, req.method, tt.wantMethod)
+				}
+				if tt.wantMethod == http.MethodPost && (req.urlQuery != "" || req.formQuery != tt.query) {
+					t.Errorf("%v query not sent in form body only, URL %q, body %q", endpoint, req.urlQuery, req.formQuery)
+				}
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestWithDataKeyPath(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name     string
+		m        *Client
+		response string
+		want     []byte
+		wantErr  bool
+	}{
+		{
+			name:     "Test WithDataKeyPath nested envelope",
+			m:        NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithDataKeyPath([]string{"prometheus"})),
+			response: `{"prometheus":` + string(unicornResponse) + `}`,
+			want:     []byte(`[{"value":[1.1,"1"]}]`),
+		},
+		{
+			name:     "Test WithDataKeyPath missing key",
+			m:        NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithDataKeyPath([]string{"prometheus"})),
+			response: string(unicornResponse),
+			wantErr:  true,
+		},
+		{
+			name:     "Test WithDataKeyPath default top level",
+			m:        NewClient("http", "127.0.0.1", "9090", WithLogger(logger)),
+			response: string(unicornResponse),
+			want:     []byte(`[{"value":[1.1,"1"]}]`),
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, tt.response)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := tt.m.QueryRequest("QUERY")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.QueryRequest() got = %s, want %s", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestWithParamName(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Date(2019, 3, 31, 1, 0, 0, 0, time.UTC)
+	end := time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		m      *Client
+		ranged bool
+		want   url.Values
+	}{
+		{
+			name: "Test WithQueryParamName query",
+			m:    NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithQueryParamName("q")),
+			want: url.Values{"q": {"QUERY"}},
+		},
+		{
+			name:   "Test WithParamName query range",
+			m:      NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithQueryParamName("q"), WithStartParamName("from"), WithEndParamName("to"), WithStepParamName("interval")),
+			ranged: true,
+			want:   url.Values{"q": {"QUERY"}, "from": {"2019-03-31T01:00:00Z"}, "to": {"2019-03-31T01:30:00Z"}, "interval": {"1m"}},
+		},
+		{
+			name:   "Test WithParamName default names",
+			m:      NewClient("http", "127.0.0.1", "9090", WithLogger(logger)),
+			ranged: true,
+			want:   url.Values{"query": {"QUERY"}, "start": {"2019-03-31T01:00:00Z"}, "end": {"2019-03-31T01:30:00Z"}, "step": {"1m"}},
+		},
+	}
+	for _, tt := range tests {
+		var got url.Values
+		httpServer := startHTTPServer("/api/v1/{endpoint}", "9090", func(w http.ResponseWriter, r *http.Request) {
+			got = r.URL.Query()
+			unicornHandler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.ranged {
+				_, _, err = tt.m.QueryRangeRequest("QUERY", start, end, time.Minute)
+			} else {
+				_, _, err = tt.m.QueryRequest("QUERY")
+			}
+			if err != nil {
+				t.Errorf("Client query error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("query params = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_QueryRequest(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	type args struct {
+		query   string
+		handler func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name    string
+		m       *Client
+		args    args
+		want    []byte
+		want1   string
+		wantErr bool
+	}{
+		{
+			name:  "Test QueryRangeRequest unicorn path",
+			m:     &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:  args{query: "QUERY", handler: unicornHandler},
+			want:  []byte(`[{"value":[1.1,"1"]}]`),
+			want1: "vector",
+		},
+		{
+			name:    "Test QueryRangeRequest data fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{query: "QUERY", handler: dataFailhandler},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query", "9090", tt.args.handler)
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.m.QueryRequest(tt.args.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.QueryRequest() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("Client.QueryRequest() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_QueryAt(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	evalTime := time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC)
+
+	type args struct {
+		query    string
+		evalTime time.Time
+		handler  func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name     string
+		m        *Client
+		args     args
+		want     *QueryResult
+		wantTime string
+		wantErr  bool
+	}{
+		{
+			name:     "Test QueryAt unicorn path",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:     args{query: "QUERY", evalTime: evalTime, handler: unicornHandler},
+			want:     &QueryResult{Result: []byte(`[{"value":[1.1,"1"]}]`), ResultType: "vector", EvalTime: evalTime},
+			wantTime: "2019-03-31T01:30:00Z",
+		},
+		{
+			name:     "Test QueryAt non-UTC millisecond time",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:     args{query: "QUERY", evalTime: time.Date(2019, 3, 31, 3, 30, 0, 250e6, time.FixedZone("CEST", 2*60*60)), handler: unicornHandler},
+			want:     &QueryResult{Result: []byte(`[{"value":[1.1,"1"]}]`), ResultType: "vector", EvalTime: evalTime.Add(250 * time.Millisecond)},
+			wantTime: "2019-03-31T01:30:00.250Z",
+		},
+		{
+			name: "Test QueryAt current time",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{query: "QUERY", handler: unicornHandler},
+			want: &QueryResult{Result: []byte(`[{"value":[1.1,"1"]}]`), ResultType: "vector"},
+		},
+		{
+			name:    "Test QueryAt data fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{query: "QUERY", evalTime: evalTime, handler: dataFailhandler},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		var gotTime string
+		httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotTime = r.URL.Query().Get("time")
+			tt.args.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now()
+			got, err := tt.m.QueryAt(tt.args.query, tt.args.evalTime)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryAt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.Result, tt.want.Result) || got.ResultType != tt.want.ResultType {
+				t.Errorf("Client.QueryAt() got = %v, want %v", got, tt.want)
+			}
+			if !tt.want.EvalTime.IsZero() && !got.EvalTime.Equal(tt.want.EvalTime) {
+				t.Errorf("Client.QueryAt() EvalTime = %v, want %v", got.EvalTime, tt.want.EvalTime)
+			}
+			if tt.want.EvalTime.IsZero() && (got.EvalTime.Before(before) || got.EvalTime.After(time.Now())) {
+				t.Errorf("Client.QueryAt() EvalTime = %v, want current time", got.EvalTime)
+			}
+			if tt.wantTime != "" && gotTime != tt.wantTime {
+				t.Errorf("Client.QueryAt() time param = %v, want %v", gotTime, tt.wantTime)
+			}
+			if gotTime != formatTime(got.EvalTime) {
+				t.Errorf("Client.QueryAt() time param = %v, want %v", gotTime, formatTime(got.EvalTime))
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_QueryAt_unknownResultType(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger))
+
+	httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"histogram_v2","result":[{"metric":{},"buckets":[[0,1,"5"]]}]}}`)
+	})
+	defer httpServer.Shutdown(context.Background())
+
+	got, err := m.QueryAt("QUERY", time.Unix(1500000000, 0))
+	if err != nil {
+		t.Fatalf("Client.QueryAt() error = %v", err)
+	}
+	if got.ResultType != ResultTypeUnknown {
+		t.Errorf("Client.QueryAt() ResultType = %v, want %v", got.ResultType, ResultTypeUnknown)
+	}
+
+	var raw []struct {
+		Buckets [][]interface{} `json:"buckets"`
+	}
+	if err := json.Unmarshal(got.Result, &raw); err != nil || len(raw) != 1 || len(raw[0].Buckets) != 1 {
+		t.Errorf("Client.QueryAt() Result = %s, want raw result, unmarshal error = %v", got.Result, err)
+	}
+
+	if _, err := ParseVector(got.Result, got.ResultType); errors.Cause(err) != ErrUnexpectedResultType {
+		t.Errorf("ParseVector() error = %v, want %v", err, ErrUnexpectedResultType)
+	}
+}
+
+func TestQueryResult_WasTruncated(t *testing.T) {
+	tests := []struct {
+		name     string
+		warnings []string
+		want     bool
+	}{
+		{
+			name: "Test WasTruncated no warnings",
+			want: false,
+		},
+		{
+			name:     "Test WasTruncated unrelated warning",
+			warnings: []string{"partial response: store unavailable"},
+			want:     false,
+		},
+		{
+			name:     "Test WasTruncated limit warning",
+			warnings: []string{"partial response: store unavailable", "results truncated due to limit"},
+			want:     true,
+		},
+		{
+			name:     "Test WasTruncated series limit warning",
+			warnings: []string{"The query hit the max number of series limit (limit: 100 series)"},
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &QueryResult{Warnings: tt.warnings}
+			if got := r.WasTruncated(); got != tt.want {
+				t.Errorf("QueryResult.WasTruncated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_QueryAt_truncated(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+
+	httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"value":[1.1,"1"]}]},"warnings":["results truncated due to limit"]}`)
+	})
+	defer httpServer.Shutdown(context.Background())
+
+	got, err := m.QueryAt("QUERY", time.Time{})
+	if err != nil {
+		t.Fatalf("Client.QueryAt() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Warnings, []string{"results truncated due to limit"}) {
+		t.Errorf("Client.QueryAt() warnings = %v", got.Warnings)
+	}
+	if !got.WasTruncated() {
+		t.Errorf("QueryResult.WasTruncated() = false, want true")
+	}
+}
+
+func TestClient_QueryRangeRequest(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	type args struct {
+		query   string
+		handler func(w http.ResponseWriter, r *http.Request)
+		start   time.Time
+		end     time.Time
+		step    time.Duration
+	}
+	tests := []struct {
+		name    string
+		m       *Client
+		args    args
+		want    []byte
+		want1   string
+		wantErr bool
+	}{
+		{
+			name:  "Test QueryRangeRequest unicorn path",
+			m:     &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:  args{query: "QUERY", handler: unicornHandler},
+			want:  []byte(`[{"value":[1.1,"1"]}]`),
+			want1: "vector",
+		},
+		{
+			name:    "Test QueryRangeRequest data fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{query: "QUERY", handler: dataFailhandler},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query_range", "9090", tt.args.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := tt.m.QueryRangeRequest(tt.args.query, tt.args.start, tt.args.end, tt.args.step)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRangeRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.QueryRangeRequest() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("Client.QueryRangeRequest() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_queryEncoding(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Date(2019, 3, 31, 1, 0, 0, 0, time.UTC)
+	end := time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		query  string
+		ranged bool
+	}{
+		{name: "Test query encoding matchers", query: `rate(http_requests_total{code=~"5.."}[5m])`},
+		{name: "Test query encoding operators", query: `sum(up{job="api"}) + 1 > 0 and on() vector(1) & 2`},
+		{name: "Test query encoding range", query: `rate(http_requests_total{code=~"5..", path!="/a?b=c&d"}[5m]) * 100`, ranged: true},
+	}
+	m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+	for _, tt := range tests {
+		var got url.Values
+		httpServer := startHTTPServer("/api/v1/{endpoint}", "9090", func(w http.ResponseWriter, r *http.Request) {
+			got = r.URL.Query()
+			unicornHandler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.ranged {
+				_, _, err = m.QueryRangeRequest(tt.query, start, end, time.Minute)
+			} else {
+				_, err = m.QueryAt(tt.query, end)
+			}
+			if err != nil {
+				t.Errorf("Client query error = %v", err)
+				return
+			}
+			if got.Get("query") != tt.query {
+				t.Errorf("query param = %v, want %v", got.Get("query"), tt.query)
+			}
+			if tt.ranged && (got.Get("start") != formatTime(start) || got.Get("end") != formatTime(end) || got.Get("step") != "1m") {
+				t.Errorf("range params = %v", got)
+			}
+			if !tt.ranged && got.Get("time") != formatTime(end) {
+				t.Errorf("time param = %v, want %v", got.Get("time"), formatTime(end))
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_QueryRequestWithContext(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Date(2019, 3, 31, 1, 0, 0, 0, time.UTC)
+	end := time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		m         *Client
+		ranged    bool
+		cancel    bool
+		handler   func(w http.ResponseWriter, r *http.Request)
+		want      []byte
+		wantCause error
+		wantErr   bool
+	}{
+		{
+			name:    "Test QueryRequestWithContext unicorn path",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: unicornHandler,
+			want:    []byte(`[{"value":[1.1,"1"]}]`),
+		},
+		{
+			name:      "Test QueryRequestWithContext cancelled",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			cancel:    true,
+			handler:   timeoutHandler,
+			wantCause: context.Canceled,
+			wantErr:   true,
+		},
+		{
+			name:    "Test QueryRangeRequestWithContext unicorn path",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			ranged:  true,
+			handler: unicornHandler,
+			want:    []byte(`[{"value":[1.1,"1"]}]`),
+		},
+		{
+			name:      "Test QueryRangeRequestWithContext cancelled",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			ranged:    true,
+			cancel:    true,
+			handler:   timeoutHandler,
+			wantCause: context.Canceled,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/{endpoint}", "9090", tt.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				time.AfterFunc(time.Millisecond*5, cancel)
+			}
+
+			var got []byte
+			var err error
+			if tt.ranged {
+				got, _, err = tt.m.QueryRangeRequestWithContext(ctx, "QUERY", start, end, time.Minute)
+			} else {
+				got, _, err = tt.m.QueryRequestWithContext(ctx, "QUERY")
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client query error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantCause != nil && !errors.Is(err, tt.wantCause) {
+				t.Errorf("Client query error = %v, want %v", err, tt.wantCause)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client query got = %s, want %s", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_QueryRequestWithContext_timeout(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name      string
+		m         *Client
+		cancel    bool
+		wantCause error
+		wantOther error
+	}{
+		{
+			name:      "Test QueryRequestWithContext client timeout",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Millisecond},
+			wantCause: context.DeadlineExceeded,
+			wantOther: context.Canceled,
+		},
+		{
+			name:      "Test QueryRequestWithContext cancelled context",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			cancel:    true,
+			wantCause: context.Canceled,
+			wantOther: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query", "9090", timeoutHandler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				time.AfterFunc(time.Millisecond*5, cancel)
+			}
+
+			_, _, err := tt.m.QueryRequestWithContext(ctx, "QUERY")
+			if err == nil {
+				t.Errorf("Client.QueryRequestWithContext() error = nil, want %v", tt.wantCause)
+				return
+			}
+			if !errors.Is(err, tt.wantCause) {
+				t.Errorf("Client.QueryRequestWithContext() error = %v, want %v", err, tt.wantCause)
+			}
+			if errors.Is(err, tt.wantOther) {
+				t.Errorf("Client.QueryRequestWithContext() error = %v, must not match %v", err, tt.wantOther)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
 }
 
-func TestNewClient(t *testing.T) {
-	logger := zap.NewExample()
-	type args struct {
-		protocol string
-		address  string
-		port     string
-		opts     []Option
-	}
+func Test_requestError(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	timeout := &net.OpError{Op: "read", Net: "tcp", Err: timeoutNetError{}}
+
 	tests := []struct {
 		name string
-		args args
-		want *Client
+		ctx  context.Context
+		err  error
+		want error
 	}{
-		{
-			name: "Test NewClient unicorn path",
-			args: args{protocol: "http", address: "127.0.0.1", port: "9090", opts: []Option{WithLogger(logger), WithTimeout(time.Second * 30)}},
-			want: &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: logger, timeout: time.Second * 30},
-		},
+		{name: "Test requestError network timeout", ctx: context.Background(), err: timeout, want: context.DeadlineExceeded},
+		{name: "Test requestError cancelled", ctx: cancelled, err: context.Canceled, want: context.Canceled},
+		{name: "Test requestError other", ctx: context.Background(), err: errors.New("connection refused"), want: nil},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewClient(tt.args.protocol, tt.args.address, tt.args.port, tt.args.opts...); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NewClient() = %v, want %v", got, tt.want)
+			got := requestError(tt.ctx, tt.err)
+			if tt.want != nil && !errors.Is(got, tt.want) {
+				t.Errorf("requestError() = %v, want %v", got, tt.want)
+			}
+			if tt.want == nil && got != tt.err {
+				t.Errorf("requestError() = %v, want %v", got, tt.err)
 			}
 		})
 	}
 }
 
-func TestClient_QueryRequest(t *testing.T) {
+// timeoutNetError net.Error reporting timeout without wrapping context.DeadlineExceeded, as older Go versions do
+type timeoutNetError struct{}
+
+func (timeoutNetError) Error() string   { return "i/o timeout" }
+func (timeoutNetError) Timeout() bool   { return true }
+func (timeoutNetError) Temporary() bool { return true }
+
+func TestClient_BuildQueryRangeURL(t *testing.T) {
 	logger := zap.NewExample(zap.Development())
+	start := time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC)
 
 	type args struct {
-		query   string
-		handler func(w http.ResponseWriter, r *http.Request)
+		query string
+		end   time.Time
+		step  time.Duration
 	}
 	tests := []struct {
 		name    string
 		m       *Client
 		args    args
-		want    []byte
-		want1   string
 		wantErr bool
 	}{
 		{
-			name:  "Test QueryRangeRequest unicorn path",
-			m:     &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: logger, timeout: time.Second * 30},
-			args:  args{query: "QUERY", handler: unicornHandler},
-			want:  []byte(`[{"value":[1.1,"1"]}]`),
-			want1: "vector",
+			name: "Test BuildQueryRangeURL unicorn path",
+			m:    NewClient("http", "127.0.0.1", "9090", WithLogger(logger)),
+			args: args{query: "up", end: start.Add(time.Hour), step: time.Minute},
 		},
 		{
-			name:    "Test QueryRangeRequest data fail",
-			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: logger, timeout: time.Second * 30},
-			args:    args{query: "QUERY", handler: dataFailhandler},
+			name: "Test BuildQueryRangeURL path prefix",
+			m:    NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithPathPrefix("/monitoring/")),
+			args: args{query: "up", end: start.Add(time.Hour), step: time.Minute * 5},
+		},
+		{
+			name:    "Test BuildQueryRangeURL strict step fail",
+			m:       NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithStrictMode()),
+			args:    args{query: "up", end: start.Add(time.Minute), step: time.Hour},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
-		httpServer := startHTTPServer("/api/v1/query", "9090", tt.args.handler)
+		var gotURL string
+		httpServer := startHTTPServer("/{path:.*}", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotURL = "http://" + r.Host + r.URL.RequestURI()
+			unicornHandler(w, r)
+		})
+
 		t.Run(tt.name, func(t *testing.T) {
-			got, got1, err := tt.m.QueryRequest(tt.args.query)
+			got, err := tt.m.BuildQueryRangeURL(tt.args.query, start, tt.args.end, tt.args.step)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Client.QueryRequest() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Client.BuildQueryRangeURL() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Client.QueryRequest() got = %v, want %v", got, tt.want)
+			if err != nil {
+				return
 			}
-			if got1 != tt.want1 {
-				t.Errorf("Client.QueryRequest() got1 = %v, want %v", got1, tt.want1)
+			if _, _, err := tt.m.QueryRangeRequest(tt.args.query, start, tt.args.end, tt.args.step); err != nil {
+				t.Fatalf("Client.QueryRangeRequest() error = %v", err)
+			}
+			if got != gotURL {
+				t.Errorf("Client.BuildQueryRangeURL() = %v, requested %v", got, gotURL)
 			}
 		})
+
 		httpServer.Shutdown(context.Background())
 	}
 }
 
-func TestClient_QueryRangeRequest(t *testing.T) {
+func TestClient_checkStep(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+
+	type args struct {
+		end  time.Time
+		step time.Duration
+	}
+	tests := []struct {
+		name        string
+		strict      bool
+		args        args
+		wantWarning bool
+		wantErr     bool
+	}{
+		{
+			name: "Test checkStep unicorn path",
+			args: args{end: start.Add(time.Hour), step: time.Minute},
+		},
+		{
+			name:        "Test checkStep step larger than window",
+			args:        args{end: start.Add(time.Hour), step: time.Hour * 2},
+			wantWarning: true,
+		},
+		{
+			name:        "Test checkStep step equal to window",
+			args:        args{end: start.Add(time.Hour), step: time.Hour},
+			wantWarning: true,
+		},
+		{
+			name:    "Test checkStep strict step larger than window",
+			strict:  true,
+			args:    args{end: start.Add(time.Hour), step: time.Hour * 2},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.WarnLevel)
+			opts := []Option{WithLogger(zap.New(core))}
+			if tt.strict {
+				opts = append(opts, WithStrictMode())
+			}
+			m := NewClient("http", "127.0.0.1", "9090", opts...)
+
+			err := m.checkStep(start, tt.args.end, tt.args.step)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.checkStep() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotWarning := logs.Len() > 0; gotWarning != tt.wantWarning {
+				t.Errorf("Client.checkStep() warning = %v, want %v", gotWarning, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestClient_QueryRangeRequestStepSeconds(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name        string
+		m           *Client
+		stepSeconds float64
+		wantStep    string
+		wantErr     bool
+	}{
+		{
+			name:        "Test QueryRangeRequestStepSeconds whole seconds",
+			m:           &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			stepSeconds: 15,
+			wantStep:    "15",
+		},
+		{
+			name:        "Test QueryRangeRequestStepSeconds fractional seconds",
+			m:           &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			stepSeconds: 0.25,
+			wantStep:    "0.25",
+		},
+		{
+			name:        "Test QueryRangeRequestStepSeconds zero",
+			m:           &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			stepSeconds: 0,
+			wantErr:     true,
+		},
+		{
+			name:        "Test QueryRangeRequestStepSeconds NaN",
+			m:           &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			stepSeconds: math.NaN(),
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		var gotStep string
+		httpServer := startHTTPServer("/api/v1/query_range", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotStep = r.URL.Query().Get("step")
+			unicornHandler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.m.QueryRangeRequestStepSeconds("QUERY", time.Now().Add(-time.Hour), time.Now(), tt.stepSeconds)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRangeRequestStepSeconds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotStep != tt.wantStep {
+				t.Errorf("Client.QueryRangeRequestStepSeconds() step = %q, want %q", gotStep, tt.wantStep)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_QueryRangeRawStep(t *testing.T) {
 	logger := zap.NewExample(zap.Development())
 
 	type args struct {
-		query   string
-		handler func(w http.ResponseWriter, r *http.Request)
-		start   time.Time
-		end     time.Time
-		step    time.Duration
+		query string
+		step  string
 	}
 	tests := []struct {
 		name    string
@@ -148,33 +1476,47 @@ func TestClient_QueryRangeRequest(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name:  "Test QueryRangeRequest unicorn path",
-			m:     &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: logger, timeout: time.Second * 30},
-			args:  args{query: "QUERY", handler: unicornHandler},
+			name:  "Test QueryRangeRawStep duration",
+			m:     &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:  args{query: "QUERY", step: "15s"},
 			want:  []byte(`[{"value":[1.1,"1"]}]`),
 			want1: "vector",
 		},
 		{
-			name:    "Test QueryRangeRequest data fail",
-			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: logger, timeout: time.Second * 30},
-			args:    args{query: "QUERY", handler: dataFailhandler},
+			name:  "Test QueryRangeRawStep seconds",
+			m:     &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:  args{query: "QUERY", step: "30"},
+			want:  []byte(`[{"value":[1.1,"1"]}]`),
+			want1: "vector",
+		},
+		{
+			name:    "Test QueryRangeRawStep invalid",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{query: "QUERY", step: "15 seconds"},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
-		httpServer := startHTTPServer("/api/v1/query_range", "9090", tt.args.handler)
+		var gotStep string
+		httpServer := startHTTPServer("/api/v1/query_range", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotStep = r.URL.Query().Get("step")
+			unicornHandler(w, r)
+		})
 
 		t.Run(tt.name, func(t *testing.T) {
-			got, got1, err := tt.m.QueryRangeRequest(tt.args.query, tt.args.start, tt.args.end, tt.args.step)
+			got, got1, err := tt.m.QueryRangeRawStep(tt.args.query, time.Now().Add(-time.Hour), time.Now(), tt.args.step)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Client.QueryRangeRequest() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Client.QueryRangeRawStep() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Client.QueryRangeRequest() got = %v, want %v", got, tt.want)
+				t.Errorf("Client.QueryRangeRawStep() got = %v, want %v", got, tt.want)
 			}
 			if got1 != tt.want1 {
-				t.Errorf("Client.QueryRangeRequest() got1 = %v, want %v", got1, tt.want1)
+				t.Errorf("Client.QueryRangeRawStep() got1 = %v, want %v", got1, tt.want1)
+			}
+			if !tt.wantErr && gotStep != tt.args.step {
+				t.Errorf("Client.QueryRangeRawStep() step = %v, want %v", gotStep, tt.args.step)
 			}
 		})
 
@@ -182,6 +1524,91 @@ func TestClient_QueryRangeRequest(t *testing.T) {
 	}
 }
 
+func Test_validStep(t *testing.T) {
+	tests := []struct {
+		step string
+		want bool
+	}{
+		{step: "15s", want: true},
+		{step: "1m", want: true},
+		{step: "1h30m", want: true},
+		{step: "500ms", want: true},
+		{step: "30", want: true},
+		{step: "0.5", want: true},
+		{step: "", want: false},
+		{step: "0", want: false},
+		{step: "-15", want: false},
+		{step: "15 seconds", want: false},
+		{step: "1.5m", want: false},
+	}
+	for _, tt := range tests {
+		t.Run("Test validStep "+tt.step, func(t *testing.T) {
+			if got := validStep(tt.step); got != tt.want {
+				t.Errorf("validStep(%q) = %v, want %v", tt.step, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_apiPath(t *testing.T) {
+	type args struct {
+		pathPrefix string
+		apiPrefix  string
+		endpoint   string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Test apiPath default",
+			args: args{endpoint: "query"},
+			want: "/api/v1/query",
+		},
+		{
+			name: "Test apiPath slashes everywhere",
+			args: args{pathPrefix: "/monitoring/", apiPrefix: "/api/v1", endpoint: "/query"},
+			want: "/monitoring/api/v1/query",
+		},
+		{
+			name: "Test apiPath no slashes",
+			args: args{pathPrefix: "monitoring", apiPrefix: "api/v1", endpoint: "query"},
+			want: "/monitoring/api/v1/query",
+		},
+		{
+			name: "Test apiPath trailing slashes",
+			args: args{pathPrefix: "/monitoring/", apiPrefix: "/api/v1/", endpoint: "query/"},
+			want: "/monitoring/api/v1/query",
+		},
+		{
+			name: "Test apiPath double slashes",
+			args: args{pathPrefix: "//monitoring//", apiPrefix: "//api/v1//", endpoint: "//query"},
+			want: "/monitoring/api/v1/query",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewClient("http", "127.0.0.1", "9090", WithPathPrefix(tt.args.pathPrefix), WithAPIPrefix(tt.args.apiPrefix))
+			if got := m.apiPath(tt.args.endpoint); got != tt.want {
+				t.Errorf("Client.apiPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPathPrefix(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithPathPrefix("/monitoring/"))
+
+	httpServer := startHTTPServer("/monitoring/api/v1/query", "9090", unicornHandler)
+	defer httpServer.Shutdown(context.Background())
+
+	if _, _, err := m.QueryRequest("QUERY"); err != nil {
+		t.Errorf("Client.QueryRequest() error = %v", err)
+	}
+}
+
 func TestClient_query(t *testing.T) {
 	logger := zap.NewExample(zap.Development())
 	type args struct {
@@ -189,32 +1616,68 @@ func TestClient_query(t *testing.T) {
 		handler func(w http.ResponseWriter, r *http.Request)
 	}
 	tests := []struct {
-		name    string
-		m       *Client
-		args    args
-		want    []byte
-		want1   string
-		wantErr bool
+		name      string
+		m         *Client
+		args      args
+		want      []byte
+		want1     string
+		wantCause error
+		wantErr   bool
 	}{
 		{
 			name:  "Test query unicorn path",
-			m:     &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: logger, timeout: time.Second * 30},
+			m:     &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
 			args:  args{query: "http://127.0.0.1:9090/api/v1/query_range", handler: unicornHandler},
 			want:  []byte(`[{"value":[1.1,"1"]}]`),
 			want1: "vector",
 		},
 		{
 			name:    "Test query data fail",
-			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: logger, timeout: time.Second * 30},
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
 			args:    args{query: "http://127.0.0.1:9090/api/v1/query_range", handler: dataFailhandler},
 			wantErr: true,
 		},
 		{
 			name:    "Test query timeout fail",
-			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: logger, timeout: time.Microsecond},
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Microsecond},
 			args:    args{query: "http://127.0.0.1:9090/api/v1/query_range", handler: timeoutHandler},
 			wantErr: true,
 		},
+		{
+			name:      "Test query empty body",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:      args{query: "http://127.0.0.1:9090/api/v1/query_range", handler: responseHandler([]byte{})},
+			wantCause: ErrEmptyResponse,
+			wantErr:   true,
+		},
+		{
+			name: "Test query bad request",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{query: "http://127.0.0.1:9090/api/v1/query_range", handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write(errorStatusResponse)
+			}},
+			wantCause: &APIError{StatusCode: http.StatusBadRequest, Type: "bad_data", Message: `1:5: parse error: unexpected "}"`},
+			wantErr:   true,
+		},
+		{
+			name: "Test query service unavailable",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{query: "http://127.0.0.1:9090/api/v1/query_range", handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			}},
+			wantCause: &StatusError{StatusCode: http.StatusServiceUnavailable, Body: "Service Unavailable\n"},
+			wantErr:   true,
+		},
+		{
+			name: "Test query status error body snippet",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{query: "http://127.0.0.1:9090/api/v1/query_range", handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, strings.Repeat("x", 2*maxErrorBodySnippet), http.StatusBadGateway)
+			}},
+			wantCause: &StatusError{StatusCode: http.StatusBadGateway, Body: strings.Repeat("x", maxErrorBodySnippet)},
+			wantErr:   true,
+		},
 	}
 	for _, tt := range tests {
 		httpServer := startHTTPServer("/api/v1/query_range", "9090", tt.args.handler)
@@ -225,6 +1688,9 @@ func TestClient_query(t *testing.T) {
 				t.Errorf("Client.query() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantCause != nil && !reflect.DeepEqual(errors.Cause(err), tt.wantCause) {
+				t.Errorf("Client.query() error = %v, want %v", err, tt.wantCause)
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Client.query() got = %v, want %v", string(got), tt.want)
 			}
@@ -242,12 +1708,13 @@ func TestClient_parseResponse(t *testing.T) {
 		data []byte
 	}
 	tests := []struct {
-		name    string
-		m       *Client
-		args    args
-		want    []byte
-		want1   string
-		wantErr bool
+		name      string
+		m         *Client
+		args      args
+		want      []byte
+		want1     string
+		wantCause error
+		wantErr   bool
 	}{
 		{
 			name:  "Test parseResponse unicorn path",
@@ -304,6 +1771,46 @@ func TestClient_parseResponse(t *testing.T) {
 			args:    args{data: resultsFailResponse2},
 			wantErr: true,
 		},
+		{
+			name:  "Test parseResponse scalar",
+			m:     &Client{},
+			args:  args{data: []byte(`{"status":"success","data":{"resultType":"scalar","result":[1500000000.5,"42"]}}`)},
+			want:  []byte(`[1500000000.5,"42"]`),
+			want1: "scalar",
+		},
+		{
+			name:  "Test parseResponse string",
+			m:     &Client{},
+			args:  args{data: []byte(`{"status":"success","data":{"resultType":"string","result":[1500000000,"foo"]}}`)},
+			want:  []byte(`[1500000000,"foo"]`),
+			want1: "string",
+		},
+		{
+			name:    "Test parseResponse scalar not pair",
+			m:       &Client{},
+			args:    args{data: []byte(`{"status":"success","data":{"resultType":"scalar","result":[1500000000]}}`)},
+			wantErr: true,
+		},
+		{
+			name:    "Test parseResponse scalar not array",
+			m:       &Client{},
+			args:    args{data: []byte(`{"status":"success","data":{"resultType":"scalar","result":"42"}}`)},
+			wantErr: true,
+		},
+		{
+			name:  "Test parseResponse unknown result type",
+			m:     &Client{logger: NewZapLogger(zap.NewNop())},
+			args:  args{data: []byte(`{"status":"success","data":{"resultType":"histogram_v2","result":{"buckets":[[0,1,"5"]]}}}`)},
+			want:  []byte(`{"buckets":[[0,1,"5"]]}`),
+			want1: ResultTypeUnknown,
+		},
+		{
+			name:      "Test parseResponse status error",
+			m:         &Client{},
+			args:      args{data: errorStatusResponse},
+			wantCause: &APIError{Type: "bad_data", Message: `1:5: parse error: unexpected "}"`},
+			wantErr:   true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -312,6 +1819,9 @@ func TestClient_parseResponse(t *testing.T) {
 				t.Errorf("Client.parseResponse() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantCause != nil && !reflect.DeepEqual(errors.Cause(err), tt.wantCause) {
+				t.Errorf("Client.parseResponse() error = %v, want %v", err, tt.wantCause)
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Client.parseResponse() got = %v, want %v", got, tt.want)
 			}
@@ -341,6 +1851,46 @@ func Test_shortDur(t *testing.T) {
 			args: args{time.Hour * 1},
 			want: "1h",
 		},
+		{
+			name: "Test shortDur sub-second",
+			args: args{time.Millisecond * 500},
+			want: "500ms",
+		},
+		{
+			name: "Test shortDur fractional seconds",
+			args: args{time.Millisecond * 1500},
+			want: "1s500ms",
+		},
+		{
+			name: "Test shortDur exact hours",
+			args: args{time.Hour * 5},
+			want: "5h",
+		},
+		{
+			name: "Test shortDur over day",
+			args: args{time.Hour * 36},
+			want: "36h",
+		},
+		{
+			name: "Test shortDur whole days",
+			args: args{time.Hour * 48},
+			want: "2d",
+		},
+		{
+			name: "Test shortDur day with seconds",
+			args: args{time.Hour*24 + time.Second*30},
+			want: "24h30s",
+		},
+		{
+			name: "Test shortDur sub-millisecond",
+			args: args{time.Microsecond * 1500},
+			want: "0.0015",
+		},
+		{
+			name: "Test shortDur zero",
+			args: args{0},
+			want: "0s",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -351,6 +1901,57 @@ func Test_shortDur(t *testing.T) {
 	}
 }
 
+func Test_formatTime(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{
+			name: "Test formatTime UTC",
+			t:    time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC),
+			want: "2019-03-31T01:30:00Z",
+		},
+		{
+			name: "Test formatTime non-UTC",
+			t:    time.Date(2019, 3, 31, 3, 30, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			want: "2019-03-31T01:30:00Z",
+		},
+		{
+			name: "Test formatTime negative offset",
+			t:    time.Date(2019, 3, 30, 20, 30, 0, 0, time.FixedZone("EST", -5*60*60)),
+			want: "2019-03-31T01:30:00Z",
+		},
+		{
+			name: "Test formatTime sub-second",
+			t:    time.Date(2019, 3, 31, 1, 30, 0, 500000000, time.UTC),
+			want: "2019-03-31T01:30:00.500Z",
+		},
+		{
+			name: "Test formatTime millisecond",
+			t:    time.Date(2019, 3, 31, 1, 30, 0, 1000000, time.UTC),
+			want: "2019-03-31T01:30:00.001Z",
+		},
+		{
+			name: "Test formatTime sub-millisecond truncated",
+			t:    time.Date(2019, 3, 31, 1, 30, 0, 123456789, time.UTC),
+			want: "2019-03-31T01:30:00.123Z",
+		},
+		{
+			name: "Test formatTime sub-millisecond only",
+			t:    time.Date(2019, 3, 31, 1, 30, 0, 999999, time.UTC),
+			want: "2019-03-31T01:30:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTime(tt.t); got != tt.want {
+				t.Errorf("formatTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_funcInfo(t *testing.T) {
 	funci := funcInfo()
 	funciArr := strings.Split(funci, ":")
@@ -375,3 +1976,27 @@ func Test_funcInfo(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkClient_query(b *testing.B) {
+	m := NewClient("http", "127.0.0.1", "9090", WithLogger(zap.NewNop()))
+
+	result := make([]string, 0, 500)
+	for i := 0; i < 500; i++ {
+		result = append(result, fmt.Sprintf(`{"metric":{"instance":"host-%d"},"value":[1500000000,"%d"]}`, i, i))
+	}
+	response := fmt.Sprintf(`{"status":"success","data":{"resultType":"vector","result":[%v]}}`, strings.Join(result, ","))
+
+	httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, response)
+	})
+	defer httpServer.Shutdown(context.Background())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := m.query("http://127.0.0.1:9090/api/v1/query"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
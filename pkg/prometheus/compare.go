@@ -0,0 +1,135 @@
+package prometheus
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// VectorDiff differences between two instant vectors, series are ordered by labels
+type VectorDiff struct {
+	// OnlyInA series returned by first client only
+	OnlyInA []Sample
+	// OnlyInB series returned by second client only
+	OnlyInB []Sample
+	// Different series returned by both clients with values outside of tolerance
+	Different []SampleDiff
+}
+
+// SampleDiff values of the same series returned by two clients
+type SampleDiff struct {
+	Metric map[string]string
+	A      float64
+	B      float64
+}
+
+// Empty reports whether both vectors matched within tolerance
+func (d VectorDiff) Empty() bool {
+	return len(d.OnlyInA) == 0 && len(d.OnlyInB) == 0 && len(d.Different) == 0
+}
+
+// CompareOption CompareQuery option
+type CompareOption func(*compareOptions)
+
+type compareOptions struct {
+	absolute float64
+	relative float64
+}
+
+// WithAbsoluteTolerance values differing by at most tolerance are considered equal
+func WithAbsoluteTolerance(tolerance float64) CompareOption {
+	return func(args *compareOptions) {
+		args.absolute = tolerance
+	}
+}
+
+// WithRelativeTolerance values differing by at most tolerance fraction of the larger magnitude are considered equal
+func WithRelativeTolerance(tolerance float64) CompareOption {
+	return func(args *compareOptions) {
+		args.relative = tolerance
+	}
+}
+
+// CompareQuery runs the same instant query against two clients and diffs resulting vectors,
+// e.g. to validate migration between Prometheus compatible backends
+// param: a, b  - clients to compare
+// param: query - Prometheus query string, must return vector
+// param: opts  - float comparison tolerances, values are compared exactly by default
+// result: VectorDiff - series missing on either side and series with different values
+func CompareQuery(a, b *Client, query string, opts ...CompareOption) (VectorDiff, error) {
+	options := &compareOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	samplesA, err := a.queryVector(query)
+	if err != nil {
+		return VectorDiff{}, errors.Wrapf(err, "%v: first query failed", funcInfo())
+	}
+
+	samplesB, err := b.queryVector(query)
+	if err != nil {
+		return VectorDiff{}, errors.Wrapf(err, "%v: second query failed", funcInfo())
+	}
+
+	return diffVectors(samplesA, samplesB, options), nil
+}
+
+func diffVectors(a, b []Sample, options *compareOptions) VectorDiff {
+	byLabels := make(map[string]Sample, len(b))
+	for _, sample := range b {
+		byLabels[labelsString(sample.Metric)] = sample
+	}
+
+	var diff VectorDiff
+
+	for _, sampleA := range a {
+		key := labelsString(sampleA.Metric)
+		sampleB, ok := byLabels[key]
+		if !ok {
+			diff.OnlyInA = append(diff.OnlyInA, sampleA)
+			continue
+		}
+		delete(byLabels, key)
+
+		if !options.equal(sampleA.Value, sampleB.Value) {
+			diff.Different = append(diff.Different, SampleDiff{Metric: sampleA.Metric, A: sampleA.Value, B: sampleB.Value})
+		}
+	}
+
+	for _, sampleB := range byLabels {
+		diff.OnlyInB = append(diff.OnlyInB, sampleB)
+	}
+
+	sortSamples(diff.OnlyInA)
+	sortSamples(diff.OnlyInB)
+	sort.Slice(diff.Different, func(i, j int) bool {
+		return labelsString(diff.Different[i].Metric) < labelsString(diff.Different[j].Metric)
+	})
+
+	return diff
+}
+
+// equal compares values within tolerances, NaN equals NaN as both sides agree there is no value
+func (o *compareOptions) equal(a, b float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	if a == b {
+		return true
+	}
+
+	delta := math.Abs(a - b)
+	if delta <= o.absolute {
+		return true
+	}
+
+	return delta <= o.relative*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func sortSamples(samples []Sample) {
+	sort.Slice(samples, func(i, j int) bool {
+		return labelsString(samples[i].Metric) < labelsString(samples[j].Metric)
+	})
+}
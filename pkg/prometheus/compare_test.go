@@ -0,0 +1,133 @@
+package prometheus
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	compareResponseA = []byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+		`{"metric":{"instance":"a"},"value":[1500000000,"1"]},` +
+		`{"metric":{"instance":"b"},"value":[1500000000,"2.0001"]},` +
+		`{"metric":{"instance":"c"},"value":[1500000000,"3"]}]}}`)
+	compareResponseB = []byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+		`{"metric":{"instance":"a"},"value":[1500000000,"1"]},` +
+		`{"metric":{"instance":"b"},"value":[1500000000,"2"]},` +
+		`{"metric":{"instance":"d"},"value":[1500000000,"4"]}]}}`)
+)
+
+func TestCompareQuery(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	a := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+	b := &Client{protocol: "http", address: "127.0.0.1", port: "9091", logger: NewZapLogger(logger), timeout: time.Second * 30}
+
+	httpServerA := startHTTPServer("/api/v1/query", "9090", responseHandler(compareResponseA))
+	defer httpServerA.Shutdown(context.Background())
+	httpServerB := startHTTPServer("/api/v1/query", "9091", responseHandler(compareResponseB))
+	defer httpServerB.Shutdown(context.Background())
+
+	ts := time.Unix(1500000000, 0)
+	onlyInA := []Sample{{Metric: map[string]string{"instance": "c"}, Value: 3, Timestamp: ts}}
+	onlyInB := []Sample{{Metric: map[string]string{"instance": "d"}, Value: 4, Timestamp: ts}}
+
+	tests := []struct {
+		name string
+		opts []CompareOption
+		want VectorDiff
+	}{
+		{
+			name: "Test CompareQuery exact",
+			want: VectorDiff{
+				OnlyInA:   onlyInA,
+				OnlyInB:   onlyInB,
+				Different: []SampleDiff{{Metric: map[string]string{"instance": "b"}, A: 2.0001, B: 2}},
+			},
+		},
+		{
+			name: "Test CompareQuery absolute tolerance",
+			opts: []CompareOption{WithAbsoluteTolerance(0.001)},
+			want: VectorDiff{OnlyInA: onlyInA, OnlyInB: onlyInB},
+		},
+		{
+			name: "Test CompareQuery relative tolerance",
+			opts: []CompareOption{WithRelativeTolerance(0.0001)},
+			want: VectorDiff{OnlyInA: onlyInA, OnlyInB: onlyInB},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompareQuery(a, b, "QUERY", tt.opts...)
+			if err != nil {
+				t.Errorf("CompareQuery() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CompareQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareQuery_queryFail(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	a := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+	b := &Client{protocol: "http", address: "127.0.0.1", port: "9091", logger: NewZapLogger(logger), timeout: time.Second * 30}
+
+	httpServerA := startHTTPServer("/api/v1/query", "9090", responseHandler(compareResponseA))
+	defer httpServerA.Shutdown(context.Background())
+	httpServerB := startHTTPServer("/api/v1/query", "9091", dataFailhandler)
+	defer httpServerB.Shutdown(context.Background())
+
+	if _, err := CompareQuery(a, b, "QUERY"); err == nil {
+		t.Errorf("CompareQuery() error = nil, want error")
+	}
+}
+
+func TestVectorDiff_Empty(t *testing.T) {
+	tests := []struct {
+		name string
+		d    VectorDiff
+		want bool
+	}{
+		{name: "Test Empty no differences", d: VectorDiff{}, want: true},
+		{name: "Test Empty different values", d: VectorDiff{Different: []SampleDiff{{A: 1, B: 2}}}, want: false},
+		{name: "Test Empty missing series", d: VectorDiff{OnlyInB: []Sample{{Value: 1}}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.Empty(); got != tt.want {
+				t.Errorf("VectorDiff.Empty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_compareOptions_equal(t *testing.T) {
+	tests := []struct {
+		name    string
+		options compareOptions
+		a, b    float64
+		want    bool
+	}{
+		{name: "Test equal exact", a: 1, b: 1, want: true},
+		{name: "Test equal exact differs", a: 1, b: 1.0000001, want: false},
+		{name: "Test equal NaN", a: math.NaN(), b: math.NaN(), want: true},
+		{name: "Test equal NaN and number", options: compareOptions{absolute: 10}, a: math.NaN(), b: 1, want: false},
+		{name: "Test equal infinity", a: math.Inf(1), b: math.Inf(1), want: true},
+		{name: "Test equal absolute", options: compareOptions{absolute: 0.5}, a: 1, b: 1.5, want: true},
+		{name: "Test equal relative", options: compareOptions{relative: 0.01}, a: 1000, b: 1010, want: true},
+		{name: "Test equal relative exceeded", options: compareOptions{relative: 0.01}, a: 1000, b: 1020, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.options.equal(tt.a, tt.b); got != tt.want {
+				t.Errorf("compareOptions.equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package prometheus
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// Compression response compression negotiated with Prometheus server via Accept-Encoding
+type Compression string
+
+const (
+	// CompressionNone requests uncompressed responses
+	CompressionNone Compression = "identity"
+	// CompressionGzip requests gzip compressed responses
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd requests zstd compressed responses, faster to decode than gzip
+	CompressionZstd Compression = "zstd"
+)
+
+// WithResponseCompression sets response compression requested from server.
+// Responses are decoded according to Content-Encoding, so servers ignoring
+// the requested encoding still work. When not set, Go HTTP transport
+// negotiates gzip transparently.
+func WithResponseCompression(compression Compression) Option {
+	return func(args *Client) {
+		args.compression = compression
+	}
+}
+
+// decompressedBody replaces response body with decoder matching its Content-Encoding
+func decompressedBody(resp *http.Response) error {
+	var body io.ReadCloser
+
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "", string(CompressionNone):
+		return nil
+	case string(CompressionGzip):
+		reader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return errors.Wrapf(err, "%v: gzip reader creation failed", funcInfo())
+		}
+		body = &decodingBody{Reader: reader, closers: []io.Closer{reader, resp.Body}}
+	case string(CompressionZstd):
+		decoder, err := zstd.NewReader(resp.Body)
+		if err != nil {
+			return errors.Wrapf(err, "%v: zstd reader creation failed", funcInfo())
+		}
+		body = &decodingBody{Reader: decoder, closers: []io.Closer{decoder.IOReadCloser(), resp.Body}}
+	default:
+		return errors.Errorf("%v: Unsupported response encoding %v", funcInfo(), resp.Header.Get("Content-Encoding"))
+	}
+
+	resp.Body = body
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+
+	return nil
+}
+
+// decodingBody decompressing response body closing both decoder and underlying body
+type decodingBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (b *decodingBody) Close() error {
+	var err error
+	for _, closer := range b.closers {
+		if cerr := closer.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,113 @@
+package prometheus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+)
+
+func compressedHandler(t *testing.T, encoding string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body bytes.Buffer
+
+		switch encoding {
+		case "gzip":
+			writer := gzip.NewWriter(&body)
+			writer.Write(unicornResponse)
+			writer.Close()
+		case "zstd":
+			writer, err := zstd.NewWriter(&body)
+			if err != nil {
+				t.Fatalf("zstd.NewWriter() error = %v", err)
+			}
+			writer.Write(unicornResponse)
+			writer.Close()
+		default:
+			body.Write(unicornResponse)
+		}
+
+		if encoding != "" {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+		w.Write(body.Bytes())
+	}
+}
+
+func TestWithResponseCompression(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	type args struct {
+		compression Compression
+		handler     func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name               string
+		args               args
+		wantAcceptEncoding string
+		want               []byte
+		wantErr            bool
+	}{
+		{
+			name:               "Test WithResponseCompression zstd",
+			args:               args{compression: CompressionZstd, handler: compressedHandler(t, "zstd")},
+			wantAcceptEncoding: "zstd",
+			want:               []byte(`[{"value":[1.1,"1"]}]`),
+		},
+		{
+			name:               "Test WithResponseCompression gzip",
+			args:               args{compression: CompressionGzip, handler: compressedHandler(t, "gzip")},
+			wantAcceptEncoding: "gzip",
+			want:               []byte(`[{"value":[1.1,"1"]}]`),
+		},
+		{
+			name:               "Test WithResponseCompression zstd ignored by server",
+			args:               args{compression: CompressionZstd, handler: compressedHandler(t, "")},
+			wantAcceptEncoding: "zstd",
+			want:               []byte(`[{"value":[1.1,"1"]}]`),
+		},
+		{
+			name:               "Test WithResponseCompression none",
+			args:               args{compression: CompressionNone, handler: compressedHandler(t, "")},
+			wantAcceptEncoding: "identity",
+			want:               []byte(`[{"value":[1.1,"1"]}]`),
+		},
+		{
+			name:               "Test WithResponseCompression unsupported encoding",
+			args:               args{compression: CompressionZstd, handler: compressedHandler(t, "br")},
+			wantAcceptEncoding: "zstd",
+			wantErr:            true,
+		},
+	}
+	for _, tt := range tests {
+		var gotAcceptEncoding string
+		httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotAcceptEncoding = r.Header.Get("Accept-Encoding")
+			tt.args.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithTimeout(time.Second*30), WithResponseCompression(tt.args.compression))
+
+			got, _, err := m.QueryRequest("QUERY")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotAcceptEncoding != tt.wantAcceptEncoding {
+				t.Errorf("Accept-Encoding = %v, want %v", gotAcceptEncoding, tt.wantAcceptEncoding)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.QueryRequest() got = %v, want %v", string(got), string(tt.want))
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
@@ -0,0 +1,87 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Config returns loaded Prometheus configuration
+// result: string - configuration as YAML
+func (m *Client) Config() (string, error) {
+	data, err := m.getData(context.Background(), "status/config", nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "%v: config request failed", funcInfo())
+	}
+
+	var config struct {
+		YAML string `json:"yaml"`
+	}
+
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return "", errors.Wrapf(err, "%v: config unmarshal failed", funcInfo())
+	}
+
+	return config.YAML, nil
+}
+
+// ScrapeInterval returns global scrape interval from Prometheus configuration,
+// result is cached after first successful request as it changes only on configuration reload
+// result: time.Duration - global.scrape_interval
+func (m *Client) ScrapeInterval() (time.Duration, error) {
+	if millis := atomic.LoadInt32(&m.scrapeIntervalMillis); millis > 0 {
+		return time.Duration(millis) * time.Millisecond, nil
+	}
+
+	config, err := m.Config()
+	if err != nil {
+		return 0, errors.Wrapf(err, "%v: config request failed", funcInfo())
+	}
+
+	interval, err := parseScrapeInterval(config)
+	if err != nil {
+		return 0, errors.Wrapf(err, "%v: scrape interval parsing failed", funcInfo())
+	}
+
+	atomic.StoreInt32(&m.scrapeIntervalMillis, int32(interval/time.Millisecond))
+
+	return interval, nil
+}
+
+// parseScrapeInterval returns global.scrape_interval of YAML configuration
+func parseScrapeInterval(config string) (time.Duration, error) {
+	var parsed struct {
+		Global struct {
+			ScrapeInterval string `yaml:"scrape_interval"`
+		} `yaml:"global"`
+	}
+
+	err := yaml.Unmarshal([]byte(config), &parsed)
+	if err != nil {
+		return 0, errors.Wrapf(err, "%v: config unmarshal failed", funcInfo())
+	}
+
+	if parsed.Global.ScrapeInterval == "" {
+		return 0, errors.Errorf("%v: Global scrape interval not set", funcInfo())
+	}
+
+	if interval, err := model.ParseDuration(parsed.Global.ScrapeInterval); err == nil && interval > 0 {
+		return time.Duration(interval), nil
+	}
+
+	interval, err := time.ParseDuration(parsed.Global.ScrapeInterval)
+	if err != nil {
+		return 0, errors.Wrapf(err, "%v: scrape interval %q parsing failed", funcInfo(), parsed.Global.ScrapeInterval)
+	}
+	if interval <= 0 {
+		return 0, errors.Errorf("%v: Scrape interval %v is not positive", funcInfo(), interval)
+	}
+
+	return interval, nil
+}
@@ -0,0 +1,94 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func configHandler(config string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := json.Marshal(map[string]interface{}{"status": "success", "data": map[string]string{"yaml": config}})
+		fmt.Fprint(w, string(body))
+	}
+}
+
+func TestClient_ScrapeInterval(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, r *http.Request)
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "Test ScrapeInterval unicorn path",
+			handler: configHandler("global:\n  scrape_interval: 30s\n  scrape_timeout: 10s\n  evaluation_interval: 1m\n" +
+				"scrape_configs:\n- job_name: prometheus\n  scrape_interval: 5s\n  static_configs:\n  - targets:\n    - localhost:9090\n"),
+			want: 30 * time.Second,
+		},
+		{
+			name:    "Test ScrapeInterval days",
+			handler: configHandler("global:\n  scrape_interval: 1d\n"),
+			want:    24 * time.Hour,
+		},
+		{
+			name:    "Test ScrapeInterval compound duration",
+			handler: configHandler("global:\n  scrape_interval: 1m30s\n"),
+			want:    90 * time.Second,
+		},
+		{
+			name:    "Test ScrapeInterval missing",
+			handler: configHandler("scrape_configs: []\n"),
+			wantErr: true,
+		},
+		{
+			name:    "Test ScrapeInterval invalid YAML",
+			handler: configHandler("global: [\n"),
+			wantErr: true,
+		},
+		{
+			name:    "Test ScrapeInterval data fail",
+			handler: dataFailhandler,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		var requests int
+		httpServer := startHTTPServer("/api/v1/status/config", "9090", func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			tt.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+
+			got, err := m.ScrapeInterval()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.ScrapeInterval() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Client.ScrapeInterval() = %v, want %v", got, tt.want)
+			}
+
+			if cached, err := m.ScrapeInterval(); err != nil || cached != tt.want {
+				t.Errorf("Client.ScrapeInterval() cached = %v, %v, want %v", cached, err, tt.want)
+			}
+			if requests != 1 {
+				t.Errorf("Client.ScrapeInterval() sent %v requests, want 1", requests)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
@@ -0,0 +1,131 @@
+package prometheus
+
+import (
+	"time"
+)
+
+// AggFunc aggregates values of single downsampling bucket, values are never empty
+type AggFunc func(values []float64) float64
+
+var (
+	// AggLast keeps the most recent value of bucket
+	AggLast AggFunc = func(values []float64) float64 {
+		return values[len(values)-1]
+	}
+	// AggAvg averages values of bucket
+	AggAvg AggFunc = func(values []float64) float64 {
+		var sum float64
+		for _, value := range values {
+			sum += value
+		}
+		return sum / float64(len(values))
+	}
+	// AggMax keeps the greatest value of bucket
+	AggMax AggFunc = func(values []float64) float64 {
+		max := values[0]
+		for _, value := range values[1:] {
+			if value > max {
+				max = value
+			}
+		}
+		return max
+	}
+)
+
+// DownsampleMatrix reduces decoded range vector to at most targetPoints points per series, e.g. for plotting.
+// Time span of all series is split into targetPoints equal buckets shared by every series, each bucket
+// is aggregated by agg and timestamped with its start. Empty buckets are skipped, not zero-filled.
+// param: streams      - decoded range vector with samples ordered by time
+// param: targetPoints - maximum number of points per series, non-positive returns unchanged copy of streams
+// param: agg          - bucket aggregation, e.g. AggLast, AggAvg or AggMax
+// result: []SampleStream - downsampled copy of streams, never sharing values or labels with streams
+func DownsampleMatrix(streams []SampleStream, targetPoints int, agg AggFunc) []SampleStream {
+	first, last, ok := matrixSpan(streams)
+	if targetPoints <= 0 || !ok {
+		copied := make([]SampleStream, 0, len(streams))
+		for _, stream := range streams {
+			copied = append(copied, copyStream(stream))
+		}
+		return copied
+	}
+
+	width := last.Sub(first) / time.Duration(targetPoints)
+
+	downsampled := make([]SampleStream, 0, len(streams))
+	for _, stream := range streams {
+		if len(stream.Values) <= targetPoints || width == 0 {
+			downsampled = append(downsampled, copyStream(stream))
+			continue
+		}
+
+		values := make([]SamplePair, 0, targetPoints)
+		var bucketValues []float64
+		bucket := int64(-1)
+
+		for _, pair := range stream.Values {
+			index := int64(pair.Timestamp.Sub(first) / width)
+			if index >= int64(targetPoints) {
+				// the latest sample of whole span belongs to the last bucket
+				index = int64(targetPoints) - 1
+			}
+			if index != bucket && len(bucketValues) > 0 {
+				values = append(values, SamplePair{Timestamp: first.Add(time.Duration(bucket) * width), Value: agg(bucketValues)})
+				bucketValues = bucketValues[:0]
+			}
+			bucket = index
+			bucketValues = append(bucketValues, pair.Value)
+		}
+		if len(bucketValues) > 0 {
+			values = append(values, SamplePair{Timestamp: first.Add(time.Duration(bucket) * width), Value: agg(bucketValues)})
+		}
+
+		downsampled = append(downsampled, SampleStream{Metric: copyLabels(stream.Metric), Values: values})
+	}
+
+	return downsampled
+}
+
+// copyStream returns stream with its own copy of labels and values
+func copyStream(stream SampleStream) SampleStream {
+	values := make([]SamplePair, len(stream.Values))
+	copy(values, stream.Values)
+
+	return SampleStream{Metric: copyLabels(stream.Metric), Values: values}
+}
+
+// copyLabels returns copy of series labels, nil stays nil
+func copyLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+
+	copied := make(map[string]string, len(labels))
+	for name, value := range labels {
+		copied[name] = value
+	}
+
+	return copied
+}
+
+// matrixSpan returns timestamps of the earliest and the latest sample of all streams
+func matrixSpan(streams []SampleStream) (time.Time, time.Time, bool) {
+	var first, last time.Time
+	ok := false
+
+	for _, stream := range streams {
+		if len(stream.Values) == 0 {
+			continue
+		}
+		streamFirst := stream.Values[0].Timestamp
+		streamLast := stream.Values[len(stream.Values)-1].Timestamp
+		if !ok || streamFirst.Before(first) {
+			first = streamFirst
+		}
+		if !ok || streamLast.After(last) {
+			last = streamLast
+		}
+		ok = true
+	}
+
+	return first, last, ok
+}
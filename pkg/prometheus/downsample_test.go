@@ -0,0 +1,121 @@
+package prometheus
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func denseStream(metric map[string]string, start time.Time, values ...float64) SampleStream {
+	stream := SampleStream{Metric: metric}
+	for i, value := range values {
+		stream.Values = append(stream.Values, SamplePair{Timestamp: start.Add(time.Duration(i) * 15 * time.Second), Value: value})
+	}
+	return stream
+}
+
+func TestDownsampleMatrix(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	dense := denseStream(map[string]string{"instance": "a"}, start, 1, 3, 5, 7, 9, 11, 2, 4, 6)
+
+	type args struct {
+		streams      []SampleStream
+		targetPoints int
+		agg          AggFunc
+	}
+	tests := []struct {
+		name string
+		args args
+		want []SampleStream
+	}{
+		{
+			name: "Test DownsampleMatrix avg",
+			args: args{streams: []SampleStream{dense}, targetPoints: 4, agg: AggAvg},
+			want: []SampleStream{{Metric: dense.Metric, Values: []SamplePair{
+				{Timestamp: start, Value: 2},
+				{Timestamp: start.Add(30 * time.Second), Value: 6},
+				{Timestamp: start.Add(60 * time.Second), Value: 10},
+				{Timestamp: start.Add(90 * time.Second), Value: 4},
+			}}},
+		},
+		{
+			name: "Test DownsampleMatrix max",
+			args: args{streams: []SampleStream{dense}, targetPoints: 2, agg: AggMax},
+			want: []SampleStream{{Metric: dense.Metric, Values: []SamplePair{
+				{Timestamp: start, Value: 7},
+				{Timestamp: start.Add(60 * time.Second), Value: 11},
+			}}},
+		},
+		{
+			name: "Test DownsampleMatrix last",
+			args: args{streams: []SampleStream{dense}, targetPoints: 2, agg: AggLast},
+			want: []SampleStream{{Metric: dense.Metric, Values: []SamplePair{
+				{Timestamp: start, Value: 7},
+				{Timestamp: start.Add(60 * time.Second), Value: 6},
+			}}},
+		},
+		{
+			name: "Test DownsampleMatrix empty buckets skipped",
+			args: args{
+				streams: []SampleStream{
+					dense,
+					{Metric: map[string]string{"instance": "b"}, Values: []SamplePair{
+						{Timestamp: start, Value: 1},
+						{Timestamp: start.Add(15 * time.Second), Value: 3},
+						{Timestamp: start.Add(120 * time.Second), Value: 5},
+					}},
+				},
+				targetPoints: 2,
+				agg:          AggAvg,
+			},
+			want: []SampleStream{
+				{Metric: dense.Metric, Values: []SamplePair{
+					{Timestamp: start, Value: 4},
+					{Timestamp: start.Add(60 * time.Second), Value: 6.4},
+				}},
+				{Metric: map[string]string{"instance": "b"}, Values: []SamplePair{
+					{Timestamp: start, Value: 2},
+					{Timestamp: start.Add(60 * time.Second), Value: 5},
+				}},
+			},
+		},
+		{
+			name: "Test DownsampleMatrix sparse series unchanged",
+			args: args{streams: []SampleStream{dense}, targetPoints: 20, agg: AggAvg},
+			want: []SampleStream{dense},
+		},
+		{
+			name: "Test DownsampleMatrix non-positive target",
+			args: args{streams: []SampleStream{dense}, agg: AggAvg},
+			want: []SampleStream{dense},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DownsampleMatrix(tt.args.streams, tt.args.targetPoints, tt.args.agg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DownsampleMatrix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownsampleMatrix_copy(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	stream := SampleStream{
+		Metric: map[string]string{"job": "api"},
+		Values: []SamplePair{{Timestamp: start, Value: 1}, {Timestamp: start.Add(time.Minute), Value: 2}},
+	}
+
+	for _, targetPoints := range []int{0, 10} {
+		streams := []SampleStream{stream}
+		got := DownsampleMatrix(streams, targetPoints, AggAvg)
+
+		got[0].Values[0].Value = 42
+		got[0].Metric["job"] = "changed"
+		got[0] = SampleStream{}
+
+		if streams[0].Values[0].Value != 1 || streams[0].Metric["job"] != "api" || len(streams[0].Values) != 2 {
+			t.Errorf("DownsampleMatrix() target %v result shares data with input %v", targetPoints, streams)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package prometheus
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// metricNameLabel label holding metric name
+	metricNameLabel = "__name__"
+	// staleNaN bit pattern Prometheus uses to mark series as stale
+	staleNaN uint64 = 0x7ff0000000000002
+)
+
+// expositionLine exposition line with metric name and rendered labels used as sort key
+type expositionLine struct {
+	name   string
+	labels string
+	text   string
+}
+
+// QueryExposition Prometheus query returns vector result as text exposition format,
+// e.g. to re-expose results to another scraper
+// param: query - Prometheus query string, must return vector
+// param: name  - metric name used for series without __name__ label, e.g. results of aggregation
+// result: []byte - one exposition line per series with labels, value and timestamp
+func (m *Client) QueryExposition(query, name string) ([]byte, error) {
+	samples, err := m.queryVector(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: vector query failed", funcInfo())
+	}
+
+	return ExpositionText(samples, name)
+}
+
+// ExpositionText formats samples as text exposition format, stale samples are omitted
+// param: samples - instant vector samples
+// param: name    - metric name used for samples without __name__ label
+// result: []byte - one exposition line per sample, ordered by metric name and labels
+func ExpositionText(samples []Sample, name string) ([]byte, error) {
+	lines := make([]expositionLine, 0, len(samples))
+
+	for _, sample := range samples {
+		if math.Float64bits(sample.Value) == staleNaN {
+			continue
+		}
+
+		metricName := sample.Metric[metricNameLabel]
+		if metricName == "" {
+			metricName = name
+		}
+		if metricName == "" {
+			return nil, errors.Errorf("%v: Sample %v has no metric name", funcInfo(), labelsString(sample.Metric))
+		}
+
+		labels := expositionLabels(sample.Metric)
+		lines = append(lines, expositionLine{
+			name:   metricName,
+			labels: labels,
+			text: metricName + labels + " " + expositionValue(sample.Value) + " " +
+				strconv.FormatInt(sample.Timestamp.UnixNano()/int64(1e6), 10) + "\n",
+		})
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].name != lines[j].name {
+			return lines[i].name < lines[j].name
+		}
+		return lines[i].labels < lines[j].labels
+	})
+
+	var buffer bytes.Buffer
+	for _, line := range lines {
+		buffer.WriteString(line.text)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// expositionLabels formats labels other than metric name as sorted {name="value",...}, empty without labels
+func expositionLabels(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for labelName := range labels {
+		if labelName != metricNameLabel {
+			names = append(names, labelName)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, labelName := range names {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		buffer.WriteString(labelName + `="` + EscapeLabelValue(labels[labelName]) + `"`)
+	}
+	buffer.WriteByte('}')
+
+	return buffer.String()
+}
+
+// expositionValue formats value with NaN and infinities spelled as exposition format requires
+func expositionValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,122 @@
+package prometheus
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var expositionResponse = []byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+	`{"metric":{"__name__":"up","job":"prometheus","instance":"localhost:9090"},"value":[1500000000.123,"1"]},` +
+	`{"metric":{"job":"node"},"value":[1500000000,"0.5"]}]}}`)
+
+func TestClient_QueryExposition(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name     string
+		m        *Client
+		response []byte
+		metric   string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Test QueryExposition unicorn path",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			response: expositionResponse,
+			metric:   "job:up:avg",
+			want: "job:up:avg{job=\"node\"} 0.5 1500000000000\n" +
+				"up{instance=\"localhost:9090\",job=\"prometheus\"} 1 1500000000123\n",
+		},
+		{
+			name:     "Test QueryExposition missing metric name",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			response: expositionResponse,
+			wantErr:  true,
+		},
+		{
+			name:     "Test QueryExposition data fail",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			response: dataFailResponse,
+			metric:   "job:up:avg",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query", "9090", responseHandler(tt.response))
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.QueryExposition("QUERY", tt.metric)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryExposition() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("Client.QueryExposition() = %q, want %q", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestExpositionText(t *testing.T) {
+	ts := time.Unix(1500000000, 0)
+
+	tests := []struct {
+		name    string
+		samples []Sample
+		want    string
+	}{
+		{
+			name: "Test ExpositionText special values",
+			samples: []Sample{
+				{Metric: map[string]string{"__name__": "a"}, Value: math.NaN(), Timestamp: ts},
+				{Metric: map[string]string{"__name__": "b"}, Value: math.Inf(1), Timestamp: ts},
+				{Metric: map[string]string{"__name__": "c"}, Value: math.Inf(-1), Timestamp: ts},
+				{Metric: map[string]string{"__name__": "d"}, Value: 1e-09, Timestamp: ts},
+			},
+			want: "a NaN 1500000000000\nb +Inf 1500000000000\nc -Inf 1500000000000\nd 1e-09 1500000000000\n",
+		},
+		{
+			name: "Test ExpositionText stale sample omitted",
+			samples: []Sample{
+				{Metric: map[string]string{"__name__": "a"}, Value: math.Float64frombits(staleNaN), Timestamp: ts},
+				{Metric: map[string]string{"__name__": "b"}, Value: 1, Timestamp: ts},
+			},
+			want: "b 1 1500000000000\n",
+		},
+		{
+			name: "Test ExpositionText metric name prefix of another name",
+			samples: []Sample{
+				{Metric: map[string]string{"__name__": "foo_bar"}, Value: 1, Timestamp: ts},
+				{Metric: map[string]string{"__name__": "foo", "job": "api"}, Value: 2, Timestamp: ts},
+				{Metric: map[string]string{"__name__": "foo"}, Value: 3, Timestamp: ts},
+			},
+			want: "foo 3 1500000000000\nfoo{job=\"api\"} 2 1500000000000\nfoo_bar 1 1500000000000\n",
+		},
+		{
+			name: "Test ExpositionText escaped label value",
+			samples: []Sample{
+				{Metric: map[string]string{"__name__": "a", "path": "C:\\dir \"x\"\n"}, Value: 1, Timestamp: ts},
+			},
+			want: "a{path=\"C:\\\\dir \\\"x\\\"\\n\"} 1 1500000000000\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpositionText(tt.samples, "")
+			if err != nil {
+				t.Errorf("ExpositionText() error = %v", err)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("ExpositionText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package prometheus
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FaultConfig faults injected into requests by WithFaultInjection, testing aid for code handling slow or failing Prometheus
+type FaultConfig struct {
+	// Rate fraction of requests faulted, from 0 (none) to 1 (all)
+	Rate float64
+	// Latency delays faulted requests, bounded by request context
+	Latency time.Duration
+	// Err is returned instead of response of faulted requests when set
+	Err error
+	// StatusCode of synthetic response returned to faulted requests when set and Err is not
+	StatusCode int
+}
+
+// WithFaultInjection injects faults into requests sent to Prometheus at configured rate. Faulted request
+// is delayed by Latency, then fails with Err or StatusCode, or is sent to server if neither is set.
+// It wraps transport of http.Client set by WithHTTPClient regardless of option order.
+func WithFaultInjection(config FaultConfig) Option {
+	return func(args *Client) {
+		args.faultInjection = &config
+	}
+}
+
+// NewFaultInjector returns RoundTripper injecting faults of config into requests sent by next,
+// http.DefaultTransport is used when next is nil
+func NewFaultInjector(next http.RoundTripper, config FaultConfig) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &faultInjector{next: next, config: config}
+}
+
+type faultInjector struct {
+	next   http.RoundTripper
+	config FaultConfig
+}
+
+func (f *faultInjector) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rand.Float64() >= f.config.Rate {
+		return f.next.RoundTrip(req)
+	}
+
+	if f.config.Latency > 0 {
+		timer := time.NewTimer(f.config.Latency)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, errors.Wrapf(req.Context().Err(), "%v: injected latency interrupted", funcInfo())
+		case <-timer.C:
+		}
+	}
+
+	if f.config.Err != nil {
+		return nil, f.config.Err
+	}
+
+	if f.config.StatusCode != 0 {
+		body := "injected fault"
+		return &http.Response{
+			Status:        http.StatusText(f.config.StatusCode),
+			StatusCode:    f.config.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"text/plain"}},
+			Body:          ioutil.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return f.next.RoundTrip(req)
+}
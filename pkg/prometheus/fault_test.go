@@ -0,0 +1,90 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+func TestWithFaultInjection(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	errInjected := errors.New("injected")
+
+	tests := []struct {
+		name        string
+		config      FaultConfig
+		calls       int
+		minFailures int
+		maxFailures int
+		minDuration time.Duration
+	}{
+		{
+			name:        "Test WithFaultInjection half status faults",
+			config:      FaultConfig{Rate: 0.5, StatusCode: http.StatusServiceUnavailable},
+			calls:       400,
+			minFailures: 140,
+			maxFailures: 260,
+		},
+		{
+			name:        "Test WithFaultInjection all errors",
+			config:      FaultConfig{Rate: 1, Err: errInjected},
+			calls:       20,
+			minFailures: 20,
+			maxFailures: 20,
+		},
+		{
+			name:        "Test WithFaultInjection latency only",
+			config:      FaultConfig{Rate: 1, Latency: time.Millisecond * 5},
+			calls:       4,
+			minDuration: time.Millisecond * 20,
+		},
+		{
+			name:  "Test WithFaultInjection disabled",
+			calls: 20,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query", "9090", unicornHandler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithFaultInjection(tt.config))
+
+			start := time.Now()
+			failures := 0
+			for i := 0; i < tt.calls; i++ {
+				if _, _, err := m.QueryRequest("QUERY"); err != nil {
+					failures++
+					if tt.config.Err != nil && !errors.Is(err, tt.config.Err) {
+						t.Errorf("Client.QueryRequest() error = %v, want %v", err, tt.config.Err)
+					}
+				}
+			}
+
+			if failures < tt.minFailures || failures > tt.maxFailures {
+				t.Errorf("Client.QueryRequest() failures = %v of %v, want between %v and %v",
+					failures, tt.calls, tt.minFailures, tt.maxFailures)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minDuration {
+				t.Errorf("Client.QueryRequest() took %v, want at least %v", elapsed, tt.minDuration)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestNewFaultInjector_latencyCanceled(t *testing.T) {
+	injector := NewFaultInjector(nil, FaultConfig{Rate: 1, Latency: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://127.0.0.1:9099/", nil)
+	if _, err := injector.RoundTrip(req); errors.Cause(err) != context.DeadlineExceeded {
+		t.Errorf("faultInjector.RoundTrip() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
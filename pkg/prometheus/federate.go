@@ -0,0 +1,63 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Federate scrapes Prometheus /federate endpoint
+// param: matches - series selectors of federated series, at least one is required
+// result: []byte - series in Prometheus text exposition format
+func (m *Client) Federate(matches []string) ([]byte, error) {
+	if len(matches) == 0 {
+		return nil, errors.Errorf("%v: At least one matcher is required", funcInfo())
+	}
+
+	params := url.Values{}
+	for _, match := range matches {
+		params.Add("match[]", match)
+	}
+
+	resp, err := m.getURLAccepting(context.Background(), m.baseURL()+m.serverPath("federate")+"?"+params.Encode(), string(expfmt.FmtText))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: federate request failed", funcInfo())
+	}
+	defer resp.Body.Close()
+
+	body, err := readBody(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: reading response body failed", funcInfo())
+	}
+	defer releaseBody(body)
+
+	if err := responseStatusError(resp, body.Bytes()); err != nil {
+		return nil, errors.Wrapf(err, "%v: federate request failed", funcInfo())
+	}
+
+	// returned slice must not alias pooled buffer
+	return append([]byte(nil), body.Bytes()...), nil
+}
+
+// FederateParsed scrapes Prometheus /federate endpoint and parses exposition into metric families
+// param: matches - series selectors of federated series, at least one is required
+// result: map[string]*dto.MetricFamily - metric families keyed by metric name
+func (m *Client) FederateParsed(matches []string) (map[string]*dto.MetricFamily, error) {
+	body, err := m.Federate(matches)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: federate failed", funcInfo())
+	}
+
+	var parser expfmt.TextParser
+
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: exposition parsing failed", funcInfo())
+	}
+
+	return families, nil
+}
@@ -0,0 +1,126 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"go.uber.org/zap"
+)
+
+var federateResponse = `# TYPE up untyped
+up{instance="localhost:9090",job="prometheus"} 1 1500000000000
+up{instance="localhost:9100",job="node"} 0 1500000000000
+# TYPE http_requests_total counter
+http_requests_total{code="200",job="api"} 1027 1500000000000
+`
+
+func TestClient_FederateParsed(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	type args struct {
+		matches []string
+		handler func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name        string
+		m           *Client
+		args        args
+		wantMatches []string
+		want        map[string]int
+		wantTypes   map[string]dto.MetricType
+		wantStatus  int
+		wantErr     bool
+	}{
+		{
+			name: "Test FederateParsed unicorn path",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{matches: []string{`{job="prometheus"}`, `{__name__=~"http_.*"}`}, handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, federateResponse)
+			}},
+			wantMatches: []string{`{job="prometheus"}`, `{__name__=~"http_.*"}`},
+			want:        map[string]int{"up": 2, "http_requests_total": 1},
+			wantTypes:   map[string]dto.MetricType{"up": dto.MetricType_UNTYPED, "http_requests_total": dto.MetricType_COUNTER},
+		},
+		{
+			name:    "Test FederateParsed matchers fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{handler: unicornHandler},
+			wantErr: true,
+		},
+		{
+			name:        "Test FederateParsed status fail",
+			m:           &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:        args{matches: []string{"up"}, handler: http.NotFound},
+			wantMatches: []string{"up"},
+			wantStatus:  http.StatusNotFound,
+			wantErr:     true,
+		},
+		{
+			name: "Test FederateParsed parsing fail",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{matches: []string{"up"}, handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "up{ 1\n")
+			}},
+			wantMatches: []string{"up"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		var gotMatches []string
+		var gotAccept string
+		httpServer := startHTTPServer("/federate", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotMatches = r.URL.Query()["match[]"]
+			gotAccept = r.Header.Get("Accept")
+			tt.args.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.FederateParsed(tt.args.matches)
+			if !reflect.DeepEqual(gotMatches, tt.wantMatches) {
+				t.Errorf("Client.FederateParsed() match[] = %v, want %v", gotMatches, tt.wantMatches)
+			}
+			if tt.wantMatches != nil && gotAccept != string(expfmt.FmtText) {
+				t.Errorf("Client.FederateParsed() Accept = %v, want %v", gotAccept, expfmt.FmtText)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.FederateParsed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var statusErr *StatusError
+			if tt.wantStatus != 0 && (!errors.As(err, &statusErr) || statusErr.StatusCode != tt.wantStatus) {
+				t.Errorf("Client.FederateParsed() error = %v, want status %v", err, tt.wantStatus)
+			}
+			var names []string
+			for name := range got {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Client.FederateParsed() families = %v, want %v", names, tt.want)
+			}
+			for name, count := range tt.want {
+				family, ok := got[name]
+				if !ok {
+					t.Errorf("Client.FederateParsed() family %v missing", name)
+					continue
+				}
+				if len(family.GetMetric()) != count {
+					t.Errorf("Client.FederateParsed() family %v metrics = %v, want %v", name, len(family.GetMetric()), count)
+				}
+				if family.GetType() != tt.wantTypes[name] {
+					t.Errorf("Client.FederateParsed() family %v type = %v, want %v", name, family.GetType(), tt.wantTypes[name])
+				}
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
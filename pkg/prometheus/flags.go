@@ -0,0 +1,72 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// flagEnableFeature command-line flag listing enabled feature flags
+	flagEnableFeature = "enable-feature"
+
+	// FeatureNativeHistograms feature flag enabling native histograms ingestion
+	FeatureNativeHistograms = "native-histograms"
+	// FeatureExemplarStorage feature flag enabling exemplar storage
+	FeatureExemplarStorage = "exemplar-storage"
+)
+
+// Flags returns command-line flags Prometheus server was started with
+// result: map[string]string - flag values by flag name, error wrapping ErrNotSupported when server does not expose them
+func (m *Client) Flags() (map[string]string, error) {
+	data, err := m.getData(context.Background(), "status/flags", nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: flags request failed", funcInfo())
+	}
+
+	var flags map[string]string
+
+	err = json.Unmarshal(data, &flags)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: flags unmarshal failed", funcInfo())
+	}
+
+	return flags, nil
+}
+
+// EnabledFeatures returns feature flags enabled by --enable-feature, e.g. to detect native histograms support
+// result: []string - enabled features, empty when server reports no features
+func (m *Client) EnabledFeatures() ([]string, error) {
+	flags, err := m.Flags()
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: flags request failed", funcInfo())
+	}
+
+	var features []string
+	for _, feature := range strings.Split(flags[flagEnableFeature], ",") {
+		if feature = strings.TrimSpace(feature); feature != "" {
+			features = append(features, feature)
+		}
+	}
+
+	return features, nil
+}
+
+// FeatureEnabled reports whether given feature flag is enabled on Prometheus server
+// param: feature - feature flag name, e.g. FeatureNativeHistograms
+func (m *Client) FeatureEnabled(feature string) (bool, error) {
+	features, err := m.EnabledFeatures()
+	if err != nil {
+		return false, errors.Wrapf(err, "%v: features request failed", funcInfo())
+	}
+
+	for _, enabled := range features {
+		if enabled == feature {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
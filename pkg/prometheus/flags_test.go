@@ -0,0 +1,102 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var flagsResponse = `{"status":"success","data":{"alertmanager.timeout":"","config.file":"/etc/prometheus/prometheus.yml",` +
+	`"enable-feature":"exemplar-storage, native-histograms","web.enable-admin-api":"false"}}`
+
+func TestClient_EnabledFeatures(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name      string
+		m         *Client
+		handler   func(w http.ResponseWriter, r *http.Request)
+		want      []string
+		wantCause error
+		wantErr   bool
+	}{
+		{
+			name: "Test EnabledFeatures unicorn path",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, flagsResponse)
+			},
+			want: []string{FeatureExemplarStorage, FeatureNativeHistograms},
+		},
+		{
+			name: "Test EnabledFeatures no features",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"status":"success","data":{"config.file":"/etc/prometheus/prometheus.yml"}}`)
+			},
+		},
+		{
+			name:      "Test EnabledFeatures not supported",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler:   http.NotFound,
+			wantCause: ErrNotSupported,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/status/flags", "9090", tt.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.EnabledFeatures()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.EnabledFeatures() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantCause != nil && errors.Cause(err) != tt.wantCause {
+				t.Errorf("Client.EnabledFeatures() error = %v, want %v", err, tt.wantCause)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.EnabledFeatures() got = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_FeatureEnabled(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+
+	httpServer := startHTTPServer("/api/v1/status/flags", "9090", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, flagsResponse)
+	})
+	defer httpServer.Shutdown(context.Background())
+
+	tests := []struct {
+		name    string
+		feature string
+		want    bool
+	}{
+		{name: "Test FeatureEnabled enabled", feature: FeatureNativeHistograms, want: true},
+		{name: "Test FeatureEnabled disabled", feature: "promql-experimental-functions", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.FeatureEnabled(tt.feature)
+			if err != nil {
+				t.Errorf("Client.FeatureEnabled() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Client.FeatureEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
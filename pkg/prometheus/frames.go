@@ -0,0 +1,69 @@
+package prometheus
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FrameFieldTime name of Frame time field
+	FrameFieldTime = "Time"
+	// FrameFieldValue name of Frame value field
+	FrameFieldValue = "Value"
+)
+
+// Frame minimal Grafana-style data frame holding single series
+type Frame struct {
+	Name   string
+	Fields []FrameField
+}
+
+// FrameField single column of Frame, time field holds Times, value field holds Values and series labels
+type FrameField struct {
+	Name   string
+	Labels map[string]string
+	Times  []time.Time
+	Values []float64
+}
+
+// QueryRangeFrames Prometheus query range returns one Frame per series
+// param: query - Prometheus query string
+// param: start - start time of range interval
+// param: end   - end time of range interval
+// param: step  - sampling interval
+// result: []Frame - frames with time field and value field labeled by series labels
+func (m *Client) QueryRangeFrames(query string, start, end time.Time, step time.Duration) ([]Frame, error) {
+	resp, resultType, err := m.QueryRangeRequest(query, start, end, step)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: query range request failed", funcInfo())
+	}
+
+	if resultType != ResultTypeMatrix {
+		return nil, errors.Wrapf(ErrUnexpectedResultType, "%v: got %v", funcInfo(), resultType)
+	}
+
+	streams, err := decodeMatrix(resp, m.maxResultSeries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: matrix decoding failed", funcInfo())
+	}
+
+	return streamsToFrames(streams), nil
+}
+
+func streamsToFrames(streams []SampleStream) []Frame {
+	frames := make([]Frame, 0, len(streams))
+	for _, stream := range streams {
+		timeField := FrameField{Name: FrameFieldTime, Times: make([]time.Time, 0, len(stream.Values))}
+		valueField := FrameField{Name: FrameFieldValue, Labels: stream.Metric, Values: make([]float64, 0, len(stream.Values))}
+
+		for _, pair := range stream.Values {
+			timeField.Times = append(timeField.Times, pair.Timestamp)
+			valueField.Values = append(valueField.Values, pair.Value)
+		}
+
+		frames = append(frames, Frame{Name: labelsString(stream.Metric), Fields: []FrameField{timeField, valueField}})
+	}
+
+	return frames
+}
@@ -0,0 +1,84 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var twoSeriesMatrixResponse = []byte(`{"status":"success","data":{"resultType":"matrix","result":[` +
+	`{"metric":{"__name__":"up","instance":"a"},"values":[[1500000000,"1"],[1500000015,"0"],[1500000030,"1"]]},` +
+	`{"metric":{"__name__":"up","instance":"b"},"values":[[1500000000,"1"],[1500000015,"1"]]}]}}`)
+
+func TestClient_QueryRangeFrames(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	type args struct {
+		query   string
+		handler func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name       string
+		m          *Client
+		args       args
+		wantLabels []map[string]string
+		wantValues [][]float64
+		wantErr    bool
+	}{
+		{
+			name: "Test QueryRangeFrames two series",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{query: "up", handler: responseHandler(twoSeriesMatrixResponse)},
+			wantLabels: []map[string]string{
+				{"__name__": "up", "instance": "a"},
+				{"__name__": "up", "instance": "b"},
+			},
+			wantValues: [][]float64{{1, 0, 1}, {1, 1}},
+		},
+		{
+			name:    "Test QueryRangeFrames result type fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{query: "up", handler: unicornHandler},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query_range", "9090", tt.args.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.QueryRangeFrames(tt.args.query, time.Unix(1500000000, 0), time.Unix(1500000030, 0), time.Second*15)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRangeFrames() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != len(tt.wantLabels) {
+				t.Fatalf("Client.QueryRangeFrames() frames = %v, want %v", len(got), len(tt.wantLabels))
+			}
+			for i, frame := range got {
+				if len(frame.Fields) != 2 {
+					t.Errorf("Frame %v fields = %v, want 2", i, len(frame.Fields))
+					continue
+				}
+				timeField, valueField := frame.Fields[0], frame.Fields[1]
+				if timeField.Name != FrameFieldTime || valueField.Name != FrameFieldValue {
+					t.Errorf("Frame %v field names = %v, %v", i, timeField.Name, valueField.Name)
+				}
+				if !reflect.DeepEqual(valueField.Labels, tt.wantLabels[i]) {
+					t.Errorf("Frame %v labels = %v, want %v", i, valueField.Labels, tt.wantLabels[i])
+				}
+				if !reflect.DeepEqual(valueField.Values, tt.wantValues[i]) {
+					t.Errorf("Frame %v values = %v, want %v", i, valueField.Values, tt.wantValues[i])
+				}
+				if len(timeField.Times) != len(valueField.Values) {
+					t.Errorf("Frame %v times = %v, values = %v", i, len(timeField.Times), len(valueField.Values))
+				}
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
@@ -0,0 +1,146 @@
+package prometheus
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WaitUntilReady polls Prometheus readiness endpoint until server reports ready
+// param: ctx          - context bounding the whole wait
+// param: pollInterval - delay between readiness checks
+func (m *Client) WaitUntilReady(ctx context.Context, pollInterval time.Duration) error {
+	if pollInterval <= 0 {
+		return errors.Errorf("%v: Poll interval must be positive, got %v", funcInfo(), pollInterval)
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "%v: waiting for readiness failed", funcInfo())
+		case <-timer.C:
+		}
+
+		if m.ready(ctx) {
+			return nil
+		}
+
+		timer.Reset(pollInterval)
+	}
+}
+
+// QueryWhenReady waits until Prometheus is ready and then runs the query, both within ctx
+// param: ctx          - context bounding readiness wait and the query
+// param: query        - Prometheus query string
+// param: pollInterval - delay between readiness checks
+// result: []byte - contains JSON marshalled type *json.RawMessage
+// result: string - contains parsed 'resultType' field from response
+func (m *Client) QueryWhenReady(ctx context.Context, query string, pollInterval time.Duration) ([]byte, string, error) {
+	if err := m.WaitUntilReady(ctx, pollInterval); err != nil {
+		return nil, "", errors.Wrapf(err, "%v: readiness check failed", funcInfo())
+	}
+
+	resp, resultType, err := m.QueryRequestWithContext(ctx, query)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "%v: query request failed", funcInfo())
+	}
+
+	return resp, resultType, nil
+}
+
+// WarmUp opens up to n connections to Prometheus with concurrent readiness requests and returns them
+// to idle pool, so that burst of queries following it does not pay connection setup latency.
+// Number of kept connections is bounded by MaxIdleConnsPerHost of transport, see WithHTTPClient.
+// param: ctx - context bounding warm-up requests
+// param: n   - number of connections to open
+func (m *Client) WarmUp(ctx context.Context, n int) error {
+	if n <= 0 {
+		return errors.Errorf("%v: Number of connections must be positive, got %v", funcInfo(), n)
+	}
+
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			errs <- m.warmUpConnection(ctx)
+		}()
+	}
+
+	var err error
+	for i := 0; i < n; i++ {
+		if connErr := <-errs; connErr != nil && err == nil {
+			err = connErr
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "%v: warm-up request failed", funcInfo())
+	}
+
+	return nil
+}
+
+// warmUpConnection requests readiness endpoint, probe drains the body so connection can be reused
+func (m *Client) warmUpConnection(ctx context.Context) error {
+	if _, err := m.probe(ctx, "-/ready"); err != nil {
+		return errors.Wrapf(err, "%v: readiness request failed", funcInfo())
+	}
+
+	return nil
+}
+
+// Healthy reports whether Prometheus health endpoint /-/healthy answered 200
+// result: bool - false when server answered with other status, e.g. 503
+// result: error - request to server failed, e.g. server is unreachable
+func (m *Client) Healthy() (bool, error) {
+	ok, err := m.probe(context.Background(), "-/healthy")
+	if err != nil {
+		return false, errors.Wrapf(err, "%v: health check failed", funcInfo())
+	}
+
+	return ok, nil
+}
+
+// Ready reports whether Prometheus readiness endpoint /-/ready answered 200, i.e. server is ready to serve queries
+// result: bool - false when server answered with other status, e.g. 503 while replaying WAL
+// result: error - request to server failed, e.g. server is unreachable
+func (m *Client) Ready() (bool, error) {
+	ok, err := m.probe(context.Background(), "-/ready")
+	if err != nil {
+		return false, errors.Wrapf(err, "%v: readiness check failed", funcInfo())
+	}
+
+	return ok, nil
+}
+
+// ready reports whether readiness endpoint answered 200, unreachable server is not ready
+func (m *Client) ready(ctx context.Context) bool {
+	ok, err := m.probe(ctx, "-/ready")
+	if err != nil {
+		m.logger.Debug("Prometheus readiness check failed", "error", err)
+		return false
+	}
+
+	return ok
+}
+
+// probe requests plain text management endpoint and reports whether it answered 200,
+// body is drained so connection can be reused
+func (m *Client) probe(ctx context.Context, endpoint string) (bool, error) {
+	resp, err := m.getURL(ctx, m.baseURL()+m.serverPath(endpoint))
+	if err != nil {
+		return false, errors.Wrapf(err, "%v: request failed", funcInfo())
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
+		return false, errors.Wrapf(err, "%v: draining response body failed", funcInfo())
+	}
+
+	return resp.StatusCode == http.StatusOK, nil
+}
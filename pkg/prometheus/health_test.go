@@ -0,0 +1,205 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptrace"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// readinessHandler reports not ready for given number of polls, then serves responses as unicornHandler
+func readinessHandler(notReadyPolls int, polls *int) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/-/ready" {
+			*polls++
+			if *polls <= notReadyPolls {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			return
+		}
+		unicornHandler(w, r)
+	}
+}
+
+func TestClient_QueryWhenReady(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	type args struct {
+		notReadyPolls int
+		timeout       time.Duration
+		pollInterval  time.Duration
+	}
+	tests := []struct {
+		name      string
+		m         *Client
+		args      args
+		want      []byte
+		want1     string
+		wantPolls int
+		wantErr   bool
+	}{
+		{
+			name:      "Test QueryWhenReady unicorn path",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:      args{notReadyPolls: 1, timeout: time.Second * 5, pollInterval: time.Millisecond * 10},
+			want:      []byte(`[{"value":[1.1,"1"]}]`),
+			want1:     "vector",
+			wantPolls: 2,
+		},
+		{
+			name:      "Test QueryWhenReady never ready",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:      args{notReadyPolls: 1000, timeout: time.Millisecond * 100, pollInterval: time.Millisecond * 10},
+			wantErr:   true,
+			wantPolls: -1,
+		},
+		{
+			name:    "Test QueryWhenReady invalid poll interval",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{timeout: time.Second * 5},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		polls := 0
+		httpServer := startHTTPServer("/{path:.*}", "9090", readinessHandler(tt.args.notReadyPolls, &polls))
+
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.args.timeout)
+			defer cancel()
+
+			got, got1, err := tt.m.QueryWhenReady(ctx, "QUERY", tt.args.pollInterval)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryWhenReady() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.QueryWhenReady() got = %s, want %s", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("Client.QueryWhenReady() got1 = %v, want %v", got1, tt.want1)
+			}
+			if tt.wantPolls >= 0 && polls != tt.wantPolls {
+				t.Errorf("Client.QueryWhenReady() readiness polls = %v, want %v", polls, tt.wantPolls)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_WarmUp(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name       string
+		n          int
+		warmUp     bool
+		wantReused bool
+		wantErr    bool
+	}{
+		{name: "Test WarmUp connection reused", n: 2, warmUp: true, wantReused: true},
+		{name: "Test WarmUp cold connection", wantReused: false},
+		{name: "Test WarmUp invalid number of connections", n: 0, warmUp: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		polls := 0
+		httpServer := startHTTPServer("/{path:.*}", "9090", readinessHandler(0, &polls))
+
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &http.Transport{}
+			defer transport.CloseIdleConnections()
+			m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithHTTPClient(&http.Client{Transport: transport}))
+
+			if tt.warmUp {
+				err := m.WarmUp(context.Background(), tt.n)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Client.WarmUp() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			var reused bool
+			ctx := httptrace.WithClientTrace(context.Background(), &httptrace.ClientTrace{
+				GotConn: func(info httptrace.GotConnInfo) {
+					reused = info.Reused
+				},
+			})
+			if _, _, err := m.QueryRequestWithContext(ctx, "QUERY"); err != nil {
+				t.Errorf("Client.QueryRequestWithContext() error = %v", err)
+				return
+			}
+			if reused != tt.wantReused {
+				t.Errorf("connection reused = %v, want %v", reused, tt.wantReused)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_HealthyReady(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name        string
+		m           *Client
+		status      int
+		wantHealthy bool
+		wantReady   bool
+		wantErr     bool
+	}{
+		{
+			name:        "Test Healthy and Ready unicorn path",
+			m:           &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			status:      http.StatusOK,
+			wantHealthy: true,
+			wantReady:   true,
+		},
+		{
+			name:        "Test Healthy but not Ready",
+			m:           &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			status:      http.StatusServiceUnavailable,
+			wantHealthy: true,
+		},
+		{
+			name:    "Test Healthy and Ready unreachable",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9099", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/-/{endpoint}", "9090", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/-/ready" {
+				w.WriteHeader(tt.status)
+			}
+			w.Write([]byte("Prometheus is Ready.\n"))
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			healthy, err := tt.m.Healthy()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Healthy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if healthy != tt.wantHealthy {
+				t.Errorf("Client.Healthy() = %v, want %v", healthy, tt.wantHealthy)
+			}
+
+			ready, err := tt.m.Ready()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Ready() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ready != tt.wantReady {
+				t.Errorf("Client.Ready() = %v, want %v", ready, tt.wantReady)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
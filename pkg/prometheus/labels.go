@@ -0,0 +1,64 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Labels returns label names of series
+// param: start   - start time of series interval, zero time is omitted
+// param: end     - end time of series interval, zero time is omitted
+// param: matches - series selectors checked by ValidateMatcher, empty means label names of all series
+// result: []string - label names
+func (m *Client) Labels(start, end time.Time, matches []string) ([]string, error) {
+	if err := validateMatchers(matches); err != nil {
+		return nil, errors.Wrapf(err, "%v: invalid matcher", funcInfo())
+	}
+
+	data, err := m.getData(context.Background(), "labels", seriesParams(matches, start, end))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: labels request failed", funcInfo())
+	}
+
+	var labels []string
+
+	err = json.Unmarshal(data, &labels)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: labels unmarshal failed", funcInfo())
+	}
+
+	return labels, nil
+}
+
+// LabelValues returns values of label across series
+// param: label   - label name, must not be empty
+// param: start   - start time of series interval, zero time is omitted
+// param: end     - end time of series interval, zero time is omitted
+// param: matches - series selectors checked by ValidateMatcher, empty means label values of all series
+// result: []string - label values
+func (m *Client) LabelValues(label string, start, end time.Time, matches []string) ([]string, error) {
+	if label == "" {
+		return nil, errors.Errorf("%v: Label name is required", funcInfo())
+	}
+	if err := validateMatchers(matches); err != nil {
+		return nil, errors.Wrapf(err, "%v: invalid matcher", funcInfo())
+	}
+
+	data, err := m.getData(context.Background(), "label/"+url.PathEscape(label)+"/values", seriesParams(matches, start, end))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: label values request failed", funcInfo())
+	}
+
+	var values []string
+
+	err = json.Unmarshal(data, &values)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: label values unmarshal failed", funcInfo())
+	}
+
+	return values, nil
+}
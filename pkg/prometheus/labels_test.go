@@ -0,0 +1,199 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestClient_Labels(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Date(2019, 3, 31, 1, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	labelsHandler := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":["__name__","instance","job"]}`)
+	}
+
+	type args struct {
+		start   time.Time
+		end     time.Time
+		matches []string
+		handler func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name       string
+		m          *Client
+		args       args
+		want       []string
+		wantParams url.Values
+		wantErr    bool
+	}{
+		{
+			name: "Test Labels unicorn path",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{start: start, end: end, matches: []string{`up{job="prometheus"}`, "process_start_time_seconds"}, handler: labelsHandler},
+			want: []string{"__name__", "instance", "job"},
+			wantParams: url.Values{
+				"start":   {"2019-03-31T01:00:00Z"},
+				"end":     {"2019-03-31T02:00:00Z"},
+				"match[]": {`up{job="prometheus"}`, "process_start_time_seconds"},
+			},
+		},
+		{
+			name:       "Test Labels no matches",
+			m:          &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:       args{start: start, end: end, handler: labelsHandler},
+			want:       []string{"__name__", "instance", "job"},
+			wantParams: url.Values{"start": {"2019-03-31T01:00:00Z"}, "end": {"2019-03-31T02:00:00Z"}},
+		},
+		{
+			name:       "Test Labels no time range",
+			m:          &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:       args{handler: labelsHandler},
+			want:       []string{"__name__", "instance", "job"},
+			wantParams: url.Values{},
+		},
+		{
+			name:    "Test Labels invalid matcher",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{matches: []string{`{job=}`}, handler: labelsHandler},
+			wantErr: true,
+		},
+		{
+			name:    "Test Labels data fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{handler: dataFailhandler},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		var gotParams url.Values
+		httpServer := startHTTPServer("/api/v1/labels", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotParams = r.URL.Query()
+			tt.args.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Labels(tt.args.start, tt.args.end, tt.args.matches)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Labels() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.Labels() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotParams, tt.wantParams) {
+				t.Errorf("Client.Labels() params = %v, want %v", gotParams, tt.wantParams)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_LabelValues(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Date(2019, 3, 31, 1, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	labelValuesHandler := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":["node","prometheus"]}`)
+	}
+
+	type args struct {
+		label   string
+		start   time.Time
+		end     time.Time
+		matches []string
+		handler func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name       string
+		m          *Client
+		args       args
+		want       []string
+		wantPath   string
+		wantParams url.Values
+		wantErr    bool
+	}{
+		{
+			name:     "Test LabelValues unicorn path",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:     args{label: "job", start: start, end: end, matches: []string{"up"}, handler: labelValuesHandler},
+			want:     []string{"node", "prometheus"},
+			wantPath: "/api/v1/label/job/values",
+			wantParams: url.Values{
+				"start":   {"2019-03-31T01:00:00Z"},
+				"end":     {"2019-03-31T02:00:00Z"},
+				"match[]": {"up"},
+			},
+		},
+		{
+			name:       "Test LabelValues escaped label",
+			m:          &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:       args{label: "k8s/app name", handler: labelValuesHandler},
+			want:       []string{"node", "prometheus"},
+			wantPath:   "/api/v1/label/k8s%2Fapp%20name/values",
+			wantParams: url.Values{},
+		},
+		{
+			name:    "Test LabelValues empty label",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{handler: labelValuesHandler},
+			wantErr: true,
+		},
+		{
+			name:    "Test LabelValues invalid matcher",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{label: "job", matches: []string{`up{job="a" instance="b"}`}, handler: labelValuesHandler},
+			wantErr: true,
+		},
+		{
+			name:    "Test LabelValues data fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{label: "job", handler: dataFailhandler},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		var gotPath string
+		var gotParams url.Values
+		httpServer := startHTTPServer("/{path:.*}", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.EscapedPath()
+			gotParams = r.URL.Query()
+			tt.args.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.LabelValues(tt.args.label, tt.args.start, tt.args.end, tt.args.matches)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.LabelValues() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.LabelValues() got = %v, want %v", got, tt.want)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("Client.LabelValues() path = %v, want %v", gotPath, tt.wantPath)
+			}
+			if !reflect.DeepEqual(gotParams, tt.wantParams) {
+				t.Errorf("Client.LabelValues() params = %v, want %v", gotParams, tt.wantParams)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
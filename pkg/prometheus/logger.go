@@ -0,0 +1,64 @@
+package prometheus
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger structured logger used by Client, message is followed by alternating keys and values.
+// Values are never modified by Client after the call, so logger may keep them and format them later.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// WithStructuredLogger sets Client logger, allowing loggers other than zap (e.g. slog or logr adapters)
+func WithStructuredLogger(logger Logger) Option {
+	return func(args *Client) {
+		args.logger = logger
+	}
+}
+
+// debugEnabler implemented by loggers able to tell whether debug entries are written,
+// so that Client can skip copying response bodies for disabled debug logging
+type debugEnabler interface {
+	DebugEnabled() bool
+}
+
+// NewZapLogger adapts zap.Logger to Logger interface
+func NewZapLogger(logger *zap.Logger) Logger {
+	logger = logger.WithOptions(zap.AddCallerSkip(1))
+	return &zapLogger{sugar: logger.Sugar(), core: logger.Core()}
+}
+
+type zapLogger struct {
+	sugar *zap.SugaredLogger
+	core  zapcore.Core
+}
+
+func (l *zapLogger) DebugEnabled() bool {
+	return l.core.Enabled(zapcore.DebugLevel)
+}
+
+func (l *zapLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.sugar.Debugw(msg, keysAndValues...)
+}
+
+func (l *zapLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.sugar.Warnw(msg, keysAndValues...)
+}
+
+func (l *zapLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.sugar.Errorw(msg, keysAndValues...)
+}
+
+// debugResponse logs response body copied out of pooled buffer, copy is skipped when logger reports
+// debug entries are not written
+func (m *Client) debugResponse(body []byte) {
+	if enabler, ok := m.logger.(debugEnabler); ok && !enabler.DebugEnabled() {
+		return
+	}
+
+	m.logger.Debug("Prometheus response", "result", string(body))
+}
@@ -0,0 +1,165 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type logEntry struct {
+	level         string
+	msg           string
+	keysAndValues []interface{}
+}
+
+type fakeLogger struct {
+	entries []logEntry
+}
+
+func (l *fakeLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, logEntry{level: "debug", msg: msg, keysAndValues: keysAndValues})
+}
+
+func (l *fakeLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, logEntry{level: "warn", msg: msg, keysAndValues: keysAndValues})
+}
+
+func (l *fakeLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, logEntry{level: "error", msg: msg, keysAndValues: keysAndValues})
+}
+
+func TestWithStructuredLogger(t *testing.T) {
+	logger := &fakeLogger{}
+	m := NewClient("http", "127.0.0.1", "9090", WithStructuredLogger(logger), WithTimeout(time.Second*30))
+
+	httpServer := startHTTPServer("/api/v1/query", "9090", unicornHandler)
+	defer httpServer.Shutdown(context.Background())
+
+	if _, _, err := m.QueryRequest("QUERY"); err != nil {
+		t.Fatalf("Client.QueryRequest() error = %v", err)
+	}
+
+	if len(logger.entries) != 2 {
+		t.Fatalf("Logger entries = %v, want 2", logger.entries)
+	}
+
+	request := logger.entries[0]
+	if request.level != "debug" || request.msg != "Prometheus request" ||
+		!reflect.DeepEqual(request.keysAndValues, []interface{}{"query", "http://127.0.0.1:9090/api/v1/query?query=QUERY"}) {
+		t.Errorf("Logger request entry = %v", request)
+	}
+
+	response := logger.entries[1]
+	if response.level != "debug" || response.msg != "Prometheus response" ||
+		!reflect.DeepEqual(response.keysAndValues, []interface{}{"result", string(unicornResponse)}) {
+		t.Errorf("Logger response entry = %v", response)
+	}
+}
+
+func TestWithStructuredLogger_retainedValues(t *testing.T) {
+	logger := &fakeLogger{}
+	m := NewClient("http", "127.0.0.1", "9090", WithStructuredLogger(logger), WithTimeout(time.Second*30))
+
+	responses := []string{
+		`{"data":{"resultType":"vector","result":[{"value":[1.1,"1"]}]}}`,
+		`{"data":{"resultType":"vector","result":[{"value":[2.2,"2"]}]}}`,
+	}
+	calls := 0
+	httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, responses[calls])
+		calls++
+	})
+	defer httpServer.Shutdown(context.Background())
+
+	for range responses {
+		if _, _, err := m.QueryRequest("QUERY"); err != nil {
+			t.Fatalf("Client.QueryRequest() error = %v", err)
+		}
+	}
+
+	var got []string
+	for _, entry := range logger.entries {
+		if entry.msg == "Prometheus response" {
+			got = append(got, fmt.Sprint(entry.keysAndValues[1]))
+		}
+	}
+	if !reflect.DeepEqual(got, responses) {
+		t.Errorf("Logger response entries = %v, want %v", got, responses)
+	}
+}
+
+func TestClient_debugResponse_disabled(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	m := NewClient("http", "127.0.0.1", "9090", WithLogger(zap.New(core)))
+
+	m.debugResponse([]byte("body"))
+
+	if logs.Len() != 0 {
+		t.Errorf("Client.debugResponse() entries = %v, want none", logs.AllUntimed())
+	}
+	if enabler, ok := m.logger.(debugEnabler); !ok || enabler.DebugEnabled() {
+		t.Errorf("zap logger at info level reports debug enabled")
+	}
+}
+
+func TestNewZapLogger(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := NewZapLogger(zap.New(core))
+
+	logger.Debug("debug message", "query", "up")
+	logger.Warn("warn message", "step", time.Minute)
+	logger.Error("error message", "result", "body")
+
+	tests := []struct {
+		name  string
+		level zapcore.Level
+		msg   string
+		field string
+		want  interface{}
+	}{
+		{
+			name:  "Test NewZapLogger debug",
+			level: zap.DebugLevel,
+			msg:   "debug message",
+			field: "query",
+			want:  "up",
+		},
+		{
+			name:  "Test NewZapLogger warn",
+			level: zap.WarnLevel,
+			msg:   "warn message",
+			field: "step",
+			want:  time.Minute,
+		},
+		{
+			name:  "Test NewZapLogger error",
+			level: zap.ErrorLevel,
+			msg:   "error message",
+			field: "result",
+			want:  "body",
+		},
+	}
+	entries := logs.AllUntimed()
+	if len(entries) != len(tests) {
+		t.Fatalf("NewZapLogger() entries = %v, want %v", len(entries), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := entries[i]
+			if entry.Level != tt.level || entry.Message != tt.msg {
+				t.Errorf("NewZapLogger() entry = %v %v, want %v %v", entry.Level, entry.Message, tt.level, tt.msg)
+			}
+			fields := entry.ContextMap()
+			if got := fields[tt.field]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewZapLogger() field %v = %v (%T), want %v", tt.field, got, got, tt.want)
+			}
+		})
+	}
+}
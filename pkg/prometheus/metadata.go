@@ -0,0 +1,49 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Meta Prometheus metric metadata
+type Meta struct {
+	Type string `json:"type"`
+	Help string `json:"help"`
+	Unit string `json:"unit"`
+}
+
+// Metadata returns metadata of scraped metrics
+// param: metric - metric name to return metadata for, empty string returns all metrics
+// param: limit  - maximum number of metrics returned, zero means no limit
+// result: map[string][]Meta - metadata keyed by metric name, metric exposed differently by several targets has multiple entries
+func (m *Client) Metadata(metric string, limit int) (map[string][]Meta, error) {
+	if limit < 0 {
+		return nil, errors.Errorf("%v: Limit must not be negative, got %v", funcInfo(), limit)
+	}
+
+	params := url.Values{}
+	if metric != "" {
+		params.Set("metric", metric)
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	data, err := m.getData(context.Background(), "metadata", params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: metadata request failed", funcInfo())
+	}
+
+	var metadata map[string][]Meta
+
+	err = json.Unmarshal(data, &metadata)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: metadata unmarshal failed", funcInfo())
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,103 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var metadataResponse = `{"status":"success","data":{` +
+	`"cortex_ring_tokens":[{"type":"gauge","help":"Number of tokens in the ring","unit":""}],` +
+	`"http_requests_total":[{"type":"counter","help":"Number of HTTP requests","unit":""},` +
+	`{"type":"counter","help":"Amount of HTTP requests","unit":""}],` +
+	`"process_cpu_seconds_total":[{"type":"counter","help":"Total user and system CPU time spent in seconds.","unit":"seconds"}]}}`
+
+func TestClient_Metadata(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	metadataHandler := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, metadataResponse)
+	}
+
+	type args struct {
+		metric  string
+		limit   int
+		handler func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name       string
+		m          *Client
+		args       args
+		want       map[string][]Meta
+		wantParams url.Values
+		wantErr    bool
+	}{
+		{
+			name: "Test Metadata unicorn path",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{handler: metadataHandler},
+			want: map[string][]Meta{
+				"cortex_ring_tokens": {{Type: "gauge", Help: "Number of tokens in the ring"}},
+				"http_requests_total": {
+					{Type: "counter", Help: "Number of HTTP requests"},
+					{Type: "counter", Help: "Amount of HTTP requests"},
+				},
+				"process_cpu_seconds_total": {{Type: "counter", Help: "Total user and system CPU time spent in seconds.", Unit: "seconds"}},
+			},
+			wantParams: url.Values{},
+		},
+		{
+			name: "Test Metadata metric and limit",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{metric: "http_requests_total", limit: 1, handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"status":"success","data":{"http_requests_total":[{"type":"counter","help":"Number of HTTP requests","unit":""}]}}`)
+			}},
+			want:       map[string][]Meta{"http_requests_total": {{Type: "counter", Help: "Number of HTTP requests"}}},
+			wantParams: url.Values{"metric": {"http_requests_total"}, "limit": {"1"}},
+		},
+		{
+			name:    "Test Metadata negative limit",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{limit: -1, handler: metadataHandler},
+			wantErr: true,
+		},
+		{
+			name:    "Test Metadata data fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{handler: dataFailhandler},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		var gotParams url.Values
+		httpServer := startHTTPServer("/api/v1/metadata", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotParams = r.URL.Query()
+			tt.args.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Metadata(tt.args.metric, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Metadata() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.Metadata() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotParams, tt.wantParams) {
+				t.Errorf("Client.Metadata() params = %v, want %v", gotParams, tt.wantParams)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
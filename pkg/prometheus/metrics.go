@@ -0,0 +1,140 @@
+package prometheus
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "prometheus_client"
+
+	// endpointOther is used for every request path outside of the known endpoint set
+	endpointOther = "other"
+	// statusError is used when request failed before any response was received
+	statusError = "error"
+)
+
+// knownEndpoints maps API path suffixes to endpoint label values.
+// Label cardinality is bounded to this set, everything else is reported as endpointOther.
+var knownEndpoints = map[string]string{
+	"query":                       "query",
+	"query_range":                 "query_range",
+	"query_exemplars":             "query_exemplars",
+	"format_query":                "format_query",
+	"series":                      "series",
+	"labels":                      "labels",
+	"label_values":                "label_values",
+	"targets":                     "targets",
+	"targets/metadata":            "targets_metadata",
+	"rules":                       "rules",
+	"alerts":                      "alerts",
+	"alertmanagers":               "alertmanagers",
+	"metadata":                    "metadata",
+	"notifications":               "notifications",
+	"status/config":               "status_config",
+	"status/flags":                "status_flags",
+	"status/buildinfo":            "status_buildinfo",
+	"status/runtimeinfo":          "status_runtimeinfo",
+	"status/tsdb":                 "status_tsdb",
+	"status/active_queries":       "status_active_queries",
+	"admin/tsdb/delete_series":    "admin_delete_series",
+	"admin/tsdb/snapshot":         "admin_snapshot",
+	"admin/tsdb/clean_tombstones": "admin_clean_tombstones",
+	"federate":                    "federate",
+	"metrics":                     "metrics",
+	"-/healthy":                   "healthy",
+	"-/ready":                     "ready",
+}
+
+type clientMetrics struct {
+	requests *promclient.CounterVec
+	duration *promclient.HistogramVec
+}
+
+func newClientMetrics(registerer promclient.Registerer, logger Logger) *clientMetrics {
+	labels := []string{"endpoint", "status"}
+
+	metrics := &clientMetrics{
+		requests: promclient.NewCounterVec(promclient.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "requests_total",
+			Help:      "Total number of requests sent to Prometheus API.",
+		}, labels),
+		duration: promclient.NewHistogramVec(promclient.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of requests sent to Prometheus API.",
+			Buckets:   promclient.DefBuckets,
+		}, labels),
+	}
+
+	metrics.requests = registerCollector(registerer, logger, metrics.requests).(*promclient.CounterVec)
+	metrics.duration = registerCollector(registerer, logger, metrics.duration).(*promclient.HistogramVec)
+
+	return metrics
+}
+
+// registerCollector registers collector, reusing already registered one
+// so that multiple Clients can share the same registerer. Other registration errors,
+// e.g. descriptor clash, are logged and collector is used unregistered.
+func registerCollector(registerer promclient.Registerer, logger Logger, collector promclient.Collector) promclient.Collector {
+	if err := registerer.Register(collector); err != nil {
+		if are, ok := err.(promclient.AlreadyRegisteredError); ok {
+			return are.ExistingCollector
+		}
+		logger.Error("Registering client metrics failed", "error", err)
+	}
+
+	return collector
+}
+
+func (cm *clientMetrics) observe(endpoint string, resp *http.Response, err error, duration time.Duration) {
+	if cm == nil {
+		return
+	}
+
+	status := statusLabel(resp, err)
+
+	cm.requests.WithLabelValues(endpoint, status).Inc()
+	cm.duration.WithLabelValues(endpoint, status).Observe(duration.Seconds())
+}
+
+// endpointLabel returns endpoint label of request path, endpoint is taken relative to API and path prefixes
+// Client is configured with, so prefixed requests are recognized as well
+func (m *Client) endpointLabel(requestPath string) string {
+	var endpoint string
+
+	apiRoot := strings.TrimSuffix(m.apiPath(""), "/") + "/"
+	serverRoot := strings.TrimSuffix(m.serverPath(), "/") + "/"
+
+	switch {
+	case strings.HasPrefix(requestPath, apiRoot):
+		endpoint = requestPath[len(apiRoot):]
+		if strings.HasPrefix(endpoint, "label/") && strings.HasSuffix(endpoint, "/values") {
+			endpoint = "label_values"
+		}
+	case strings.HasPrefix(requestPath, serverRoot):
+		endpoint = requestPath[len(serverRoot):]
+	default:
+		return endpointOther
+	}
+
+	if label, ok := knownEndpoints[strings.Trim(endpoint, "/")]; ok {
+		return label
+	}
+
+	return endpointOther
+}
+
+// statusLabel returns status code class (2xx, 4xx, ...) to keep label cardinality bounded
+func statusLabel(resp *http.Response, err error) string {
+	if err != nil || resp == nil {
+		return statusError
+	}
+
+	return strconv.Itoa(resp.StatusCode/100) + "xx"
+}
@@ -0,0 +1,204 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithMetrics(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	registry := promclient.NewRegistry()
+
+	m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithTimeout(time.Second*30), WithMetrics(registry))
+
+	httpServer := startHTTPServer("/api/v1/{endpoint}", "9090", unicornHandler)
+	defer httpServer.Shutdown(context.Background())
+
+	if _, _, err := m.QueryRequest("QUERY"); err != nil {
+		t.Fatalf("Client.QueryRequest() error = %v", err)
+	}
+	if _, _, err := m.QueryRangeRequest("QUERY", time.Now().Add(-time.Hour), time.Now(), time.Minute); err != nil {
+		t.Fatalf("Client.QueryRangeRequest() error = %v", err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Registry.Gather() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		metric   string
+		endpoint string
+		status   string
+	}{
+		{
+			name:     "Test WithMetrics requests query",
+			metric:   "prometheus_client_requests_total",
+			endpoint: "query",
+			status:   "2xx",
+		},
+		{
+			name:     "Test WithMetrics requests query_range",
+			metric:   "prometheus_client_requests_total",
+			endpoint: "query_range",
+			status:   "2xx",
+		},
+		{
+			name:     "Test WithMetrics duration query",
+			metric:   "prometheus_client_request_duration_seconds",
+			endpoint: "query",
+			status:   "2xx",
+		},
+		{
+			name:     "Test WithMetrics duration query_range",
+			metric:   "prometheus_client_request_duration_seconds",
+			endpoint: "query_range",
+			status:   "2xx",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, family := range families {
+				if family.GetName() != tt.metric {
+					continue
+				}
+				for _, metric := range family.GetMetric() {
+					labels := map[string]string{}
+					for _, pair := range metric.GetLabel() {
+						labels[pair.GetName()] = pair.GetValue()
+					}
+					if labels["endpoint"] == tt.endpoint && labels["status"] == tt.status {
+						return
+					}
+				}
+			}
+			t.Errorf("metric %v{endpoint=%q,status=%q} not found", tt.metric, tt.endpoint, tt.status)
+		})
+	}
+}
+
+func TestWithMetrics_registrationError(t *testing.T) {
+	registry := promclient.NewRegistry()
+	registry.MustRegister(promclient.NewCounterVec(promclient.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "requests_total",
+		Help:      "Clashing counter with different labels.",
+	}, []string{"method"}))
+
+	core, logs := observer.New(zap.ErrorLevel)
+	NewClient("http", "127.0.0.1", "9090", WithMetrics(registry), WithLogger(zap.New(core)))
+
+	if entries := logs.FilterMessage("Registering client metrics failed").AllUntimed(); len(entries) != 1 {
+		t.Errorf("NewClient() logged %v, want one registration error", logs.AllUntimed())
+	}
+}
+
+func TestClient_endpointLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Client
+		path string
+		want string
+	}{
+		{
+			name: "Test endpointLabel query",
+			m:    &Client{},
+			path: "/api/v1/query",
+			want: "query",
+		},
+		{
+			name: "Test endpointLabel path prefixed query_range",
+			m:    &Client{pathPrefix: "/monitoring"},
+			path: "/monitoring/api/v1/query_range",
+			want: "query_range",
+		},
+		{
+			name: "Test endpointLabel API prefixed query",
+			m:    &Client{apiPrefix: "/prometheus/api/v1"},
+			path: "/prometheus/api/v1/query",
+			want: "query",
+		},
+		{
+			name: "Test endpointLabel custom API prefix",
+			m:    &Client{pathPrefix: "/tenant", apiPrefix: "/select/0/prometheus"},
+			path: "/tenant/select/0/prometheus/status/active_queries",
+			want: "status_active_queries",
+		},
+		{
+			name: "Test endpointLabel label values",
+			m:    &Client{},
+			path: "/api/v1/label/job/values",
+			want: "label_values",
+		},
+		{
+			name: "Test endpointLabel ready",
+			m:    &Client{},
+			path: "/-/ready",
+			want: "ready",
+		},
+		{
+			name: "Test endpointLabel path prefixed federate",
+			m:    &Client{pathPrefix: "/monitoring"},
+			path: "/monitoring/federate",
+			want: "federate",
+		},
+		{
+			name: "Test endpointLabel outside of prefix",
+			m:    &Client{pathPrefix: "/monitoring"},
+			path: "/api/v1/query",
+			want: endpointOther,
+		},
+		{
+			name: "Test endpointLabel unknown",
+			m:    &Client{},
+			path: "/api/v1/some/unknown/path",
+			want: endpointOther,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.endpointLabel(tt.path); got != tt.want {
+				t.Errorf("Client.endpointLabel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_statusLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *http.Response
+		err  error
+		want string
+	}{
+		{
+			name: "Test statusLabel ok",
+			resp: &http.Response{StatusCode: http.StatusOK},
+			want: "2xx",
+		},
+		{
+			name: "Test statusLabel unavailable",
+			resp: &http.Response{StatusCode: http.StatusServiceUnavailable},
+			want: "5xx",
+		},
+		{
+			name: "Test statusLabel error",
+			err:  context.Canceled,
+			want: statusError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusLabel(tt.resp, tt.err); got != tt.want {
+				t.Errorf("statusLabel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
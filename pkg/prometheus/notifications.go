@@ -0,0 +1,168 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Notification Prometheus server notification
+type Notification struct {
+	Text   string    `json:"text"`
+	Date   time.Time `json:"date"`
+	Active bool      `json:"active"`
+}
+
+// Notifications returns Prometheus server notifications
+// result: []Notification - notification entries, error wrapping ErrNotSupported when server does not expose them
+func (m *Client) Notifications() ([]Notification, error) {
+	data, err := m.getData(context.Background(), "notifications", nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: notifications request failed", funcInfo())
+	}
+
+	var notifications []Notification
+
+	err = json.Unmarshal(data, &notifications)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: notifications unmarshal failed", funcInfo())
+	}
+
+	return notifications, nil
+}
+
+const (
+	metricNotificationsQueueLength   = "prometheus_notifications_queue_length"
+	metricNotificationsQueueCapacity = "prometheus_notifications_queue_capacity"
+	metricNotificationsDropped       = "prometheus_notifications_dropped_total"
+	metricNotificationsSent          = "prometheus_notifications_sent_total"
+	metricNotificationsErrors        = "prometheus_notifications_errors_total"
+	metricNotificationsLatency       = "prometheus_notifications_latency_seconds"
+
+	alertmanagerLabel = "alertmanager"
+)
+
+// NotificationStats Prometheus alert notification dispatch statistics
+type NotificationStats struct {
+	QueueLength   float64
+	QueueCapacity float64
+	Dropped       float64
+	Alertmanagers map[string]AlertmanagerNotificationStats
+}
+
+// AlertmanagerNotificationStats notification dispatch statistics of single Alertmanager
+type AlertmanagerNotificationStats struct {
+	Sent   float64
+	Errors float64
+	// LatencyQuantiles dispatch latency keyed by quantile, e.g. 0.99
+	LatencyQuantiles map[float64]time.Duration
+	// LatencyMean mean dispatch latency since server start
+	LatencyMean time.Duration
+}
+
+// NotificationStats returns alert notification queue and dispatch latency statistics.
+// Prometheus does not expose them via status API, so they are scraped from prometheus_notifications_* series of /metrics.
+// result: *NotificationStats - queue statistics and per-Alertmanager statistics keyed by Alertmanager URL
+func (m *Client) NotificationStats() (*NotificationStats, error) {
+	resp, err := m.getURLAccepting(context.Background(), m.baseURL()+m.serverPath("metrics"), string(expfmt.FmtText))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: metrics request failed", funcInfo())
+	}
+	defer resp.Body.Close()
+
+	body, err := readBody(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: reading response body failed", funcInfo())
+	}
+	defer releaseBody(body)
+
+	if err := responseStatusError(resp, body.Bytes()); err != nil {
+		return nil, errors.Wrapf(err, "%v: metrics request failed", funcInfo())
+	}
+
+	stats, err := parseNotificationStats(body.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: notification stats parsing failed", funcInfo())
+	}
+
+	return stats, nil
+}
+
+// parseNotificationStats extracts notification statistics from text exposition, unrelated series are ignored
+func parseNotificationStats(exposition []byte) (*NotificationStats, error) {
+	var parser expfmt.TextParser
+
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(exposition))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: exposition parsing failed", funcInfo())
+	}
+
+	if _, ok := families[metricNotificationsQueueLength]; !ok {
+		return nil, errors.Errorf("%v: Metric %v not found", funcInfo(), metricNotificationsQueueLength)
+	}
+
+	stats := &NotificationStats{Alertmanagers: map[string]AlertmanagerNotificationStats{}}
+
+	for name, family := range families {
+		for _, metric := range family.GetMetric() {
+			switch name {
+			case metricNotificationsQueueLength:
+				stats.QueueLength = metric.GetGauge().GetValue()
+			case metricNotificationsQueueCapacity:
+				stats.QueueCapacity = metric.GetGauge().GetValue()
+			case metricNotificationsDropped:
+				stats.Dropped = metric.GetCounter().GetValue()
+			case metricNotificationsSent, metricNotificationsErrors, metricNotificationsLatency:
+				alertmanager := labelValue(metric, alertmanagerLabel)
+				stats.Alertmanagers[alertmanager] = alertmanagerStats(stats.Alertmanagers[alertmanager], name, metric)
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// alertmanagerStats returns Alertmanager statistics updated by value of given notification metric
+func alertmanagerStats(stats AlertmanagerNotificationStats, name string, metric *dto.Metric) AlertmanagerNotificationStats {
+	switch name {
+	case metricNotificationsSent:
+		stats.Sent = metric.GetCounter().GetValue()
+	case metricNotificationsErrors:
+		stats.Errors = metric.GetCounter().GetValue()
+	case metricNotificationsLatency:
+		summary := metric.GetSummary()
+		stats.LatencyQuantiles = map[float64]time.Duration{}
+		for _, quantile := range summary.GetQuantile() {
+			if math.IsNaN(quantile.GetValue()) {
+				continue
+			}
+			stats.LatencyQuantiles[quantile.GetQuantile()] = secondsToDuration(quantile.GetValue())
+		}
+		if summary.GetSampleCount() > 0 {
+			stats.LatencyMean = secondsToDuration(summary.GetSampleSum() / float64(summary.GetSampleCount()))
+		}
+	}
+
+	return stats
+}
+
+// labelValue returns value of named label of metric, empty string when it is not set
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+
+	return ""
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
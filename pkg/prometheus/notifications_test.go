@@ -0,0 +1,190 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var notificationsResponse = `{"status":"success","data":[` +
+	`{"text":"Configuration reload has failed.","date":"2024-10-07T12:33:08.551376578+02:00","active":true},` +
+	`{"text":"Prometheus is shutting down and gracefully stopping all operations.","date":"2024-10-07T12:30:00Z","active":false}]}`
+
+func TestClient_Notifications(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name      string
+		m         *Client
+		handler   func(w http.ResponseWriter, r *http.Request)
+		want      []Notification
+		wantCause error
+		wantErr   bool
+	}{
+		{
+			name: "Test Notifications unicorn path",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, notificationsResponse)
+			},
+			want: []Notification{
+				{Text: "Configuration reload has failed.", Date: time.Date(2024, 10, 7, 10, 33, 8, 551376578, time.UTC), Active: true},
+				{Text: "Prometheus is shutting down and gracefully stopping all operations.", Date: time.Date(2024, 10, 7, 12, 30, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:      "Test Notifications not supported",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler:   http.NotFound,
+			wantCause: ErrNotSupported,
+			wantErr:   true,
+		},
+		{
+			name: "Test Notifications service unavailable",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/notifications", "9090", tt.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Notifications()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Notifications() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantCause != nil && errors.Cause(err) != tt.wantCause {
+				t.Errorf("Client.Notifications() error = %v, want %v", err, tt.wantCause)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Client.Notifications() got = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Text != tt.want[i].Text || got[i].Active != tt.want[i].Active || !got[i].Date.Equal(tt.want[i].Date) {
+					t.Errorf("Client.Notifications() got = %v, want %v", got[i], tt.want[i])
+				}
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+var notificationMetricsResponse = `# HELP go_goroutines Number of goroutines that currently exist.
+# TYPE go_goroutines gauge
+go_goroutines 42
+# HELP prometheus_notifications_alertmanagers_discovered The number of alertmanagers discovered and active.
+# TYPE prometheus_notifications_alertmanagers_discovered gauge
+prometheus_notifications_alertmanagers_discovered 2
+# HELP prometheus_notifications_dropped_total Total number of alerts dropped due to errors when sending to Alertmanager.
+# TYPE prometheus_notifications_dropped_total counter
+prometheus_notifications_dropped_total 3
+# HELP prometheus_notifications_errors_total Total number of errors sending alert notifications.
+# TYPE prometheus_notifications_errors_total counter
+prometheus_notifications_errors_total{alertmanager="http://am-0:9093/api/v2/alerts"} 0
+prometheus_notifications_errors_total{alertmanager="http://am-1:9093/api/v2/alerts"} 3
+# HELP prometheus_notifications_latency_seconds Latency quantiles for sending alert notifications.
+# TYPE prometheus_notifications_latency_seconds summary
+prometheus_notifications_latency_seconds{alertmanager="http://am-0:9093/api/v2/alerts",quantile="0.5"} 0.002
+prometheus_notifications_latency_seconds{alertmanager="http://am-0:9093/api/v2/alerts",quantile="0.99"} 0.015
+prometheus_notifications_latency_seconds_sum{alertmanager="http://am-0:9093/api/v2/alerts"} 0.4
+prometheus_notifications_latency_seconds_count{alertmanager="http://am-0:9093/api/v2/alerts"} 100
+prometheus_notifications_latency_seconds{alertmanager="http://am-1:9093/api/v2/alerts",quantile="0.5"} NaN
+prometheus_notifications_latency_seconds{alertmanager="http://am-1:9093/api/v2/alerts",quantile="0.99"} NaN
+prometheus_notifications_latency_seconds_sum{alertmanager="http://am-1:9093/api/v2/alerts"} 0
+prometheus_notifications_latency_seconds_count{alertmanager="http://am-1:9093/api/v2/alerts"} 0
+# HELP prometheus_notifications_queue_capacity The capacity of the alert notifications queue.
+# TYPE prometheus_notifications_queue_capacity gauge
+prometheus_notifications_queue_capacity 10000
+# HELP prometheus_notifications_queue_length The number of alert notifications in the queue.
+# TYPE prometheus_notifications_queue_length gauge
+prometheus_notifications_queue_length 7
+# HELP prometheus_notifications_sent_total Total number of alerts sent.
+# TYPE prometheus_notifications_sent_total counter
+prometheus_notifications_sent_total{alertmanager="http://am-0:9093/api/v2/alerts"} 100
+prometheus_notifications_sent_total{alertmanager="http://am-1:9093/api/v2/alerts"} 0
+`
+
+func TestClient_NotificationStats(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name    string
+		m       *Client
+		handler func(w http.ResponseWriter, r *http.Request)
+		want    *NotificationStats
+		wantErr bool
+	}{
+		{
+			name: "Test NotificationStats unicorn path",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, notificationMetricsResponse)
+			},
+			want: &NotificationStats{
+				QueueLength:   7,
+				QueueCapacity: 10000,
+				Dropped:       3,
+				Alertmanagers: map[string]AlertmanagerNotificationStats{
+					"http://am-0:9093/api/v2/alerts": {
+						Sent:             100,
+						LatencyQuantiles: map[float64]time.Duration{0.5: 2 * time.Millisecond, 0.99: 15 * time.Millisecond},
+						LatencyMean:      4 * time.Millisecond,
+					},
+					"http://am-1:9093/api/v2/alerts": {
+						Errors:           3,
+						LatencyQuantiles: map[float64]time.Duration{},
+					},
+				},
+			},
+		},
+		{
+			name: "Test NotificationStats notifier metrics missing",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "go_goroutines 42\n")
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test NotificationStats invalid exposition",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "prometheus_notifications_queue_length{\n")
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Test NotificationStats not found",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: http.NotFound,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/metrics", "9090", tt.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.NotificationStats()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.NotificationStats() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.NotificationStats() got = %+v, want %+v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
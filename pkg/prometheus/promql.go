@@ -0,0 +1,273 @@
+package prometheus
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// EscapeLabelValue escapes backslashes, double quotes and newlines in label value
+// result: string - PromQL-safe body of double-quoted string literal, without the surrounding quotes
+func EscapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
+
+// Selector builds PromQL series selector from metric name and label equality matchers
+// param: metric - metric name, may be empty
+// param: labels - label names and values, values are escaped with EscapeLabelValue
+// result: string - selector such as metric{instance="a",job="api"}
+// result: error - empty metric without labels, Prometheus rejects {} selector
+func Selector(metric string, labels map[string]string) (string, error) {
+	if len(labels) == 0 {
+		if metric == "" {
+			return "", errors.Errorf("%v: Selector must contain metric name or at least one matcher", funcInfo())
+		}
+		return metric, nil
+	}
+
+	return metric + labelsString(labels), nil
+}
+
+// CanonicalizeQuery returns canonical form of PromQL query usable as cache or dedup key.
+// Whitespace and comments outside of string literals are dropped, single space is kept only
+// where it separates two words (e.g. "sum by", "a and b"), so "rate( x [5m] )" and "rate(x[5m])"
+// canonicalize identically. Unterminated strings and unbalanced brackets are reported as errors.
+func CanonicalizeQuery(query string) (string, error) {
+	var canonical strings.Builder
+	var brackets []rune
+
+	runes := []rune(query)
+	space := false
+	lastWord := false
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			space = true
+		case r == '"' || r == '\'' || r == '`':
+			end := stringLiteralEnd(runes, i)
+			if end < 0 {
+				return "", errors.Errorf("%v: Unterminated string literal at position %v", funcInfo(), i)
+			}
+			canonical.WriteString(string(runes[i : end+1]))
+			i = end
+			lastWord = false
+			space = false
+		case isWordRune(r):
+			if space && lastWord {
+				canonical.WriteRune(' ')
+			}
+			for i < len(runes) && isWordRune(runes[i]) {
+				canonical.WriteRune(runes[i])
+				i++
+			}
+			i--
+			lastWord = true
+			space = false
+		default:
+			switch r {
+			case '(', '[', '{':
+				brackets = append(brackets, r)
+			case ')', ']', '}':
+				if len(brackets) == 0 || brackets[len(brackets)-1] != openingBracket(r) {
+					return "", errors.Errorf("%v: Unbalanced %q at position %v", funcInfo(), r, i)
+				}
+				brackets = brackets[:len(brackets)-1]
+			}
+			canonical.WriteRune(r)
+			lastWord = false
+			space = false
+		}
+	}
+
+	if len(brackets) > 0 {
+		return "", errors.Errorf("%v: Unclosed %q", funcInfo(), brackets[len(brackets)-1])
+	}
+
+	return canonical.String(), nil
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r == ':' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func openingBracket(r rune) rune {
+	switch r {
+	case ')':
+		return '('
+	case ']':
+		return '['
+	default:
+		return '{'
+	}
+}
+
+// stringLiteralEnd returns index of closing quote of string literal starting at start or -1
+func stringLiteralEnd(runes []rune, start int) int {
+	quote := runes[start]
+
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && quote != '`':
+			i++
+		case runes[i] == quote:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// ValidateMatcher checks syntax of series selector locally, e.g. before it is sent as match[] param.
+// Selector is optional metric name followed by optional {label<op>"value",...} matchers with op one of
+// =, !=, =~, !~ and value quoted by ", ' or `. At least metric name or one matcher is required.
+// result: error - describing first syntax error, nil for valid selector
+func ValidateMatcher(selector string) error {
+	runes := []rune(selector)
+
+	i := skipSpaces(runes, 0)
+	start := i
+	for i < len(runes) && isNameRune(runes[i], i == start, true) {
+		i++
+	}
+	hasName := i > start
+
+	i = skipSpaces(runes, i)
+	if i == len(runes) {
+		if !hasName {
+			return errors.Errorf("%v: Selector is empty", funcInfo())
+		}
+		return nil
+	}
+
+	if runes[i] != '{' {
+		return errors.Errorf("%v: Unexpected %q at position %v", funcInfo(), runes[i], i)
+	}
+	i++
+
+	matchers := 0
+	for {
+		i = skipSpaces(runes, i)
+		if i == len(runes) {
+			return errors.Errorf("%v: Unclosed '{'", funcInfo())
+		}
+		if runes[i] == '}' {
+			i++
+			break
+		}
+
+		nameStart := i
+		for i < len(runes) && isNameRune(runes[i], i == nameStart, false) {
+			i++
+		}
+		if i == nameStart {
+			return errors.Errorf("%v: Expected label name at position %v", funcInfo(), i)
+		}
+		label := string(runes[nameStart:i])
+
+		i = skipSpaces(runes, i)
+		op := matchOperatorLen(runes, i)
+		if op == 0 {
+			return errors.Errorf("%v: Expected matching operator after label %q", funcInfo(), label)
+		}
+
+		i = skipSpaces(runes, i+op)
+		if i == len(runes) || !isQuote(runes[i]) {
+			return errors.Errorf("%v: Expected quoted value of label %q", funcInfo(), label)
+		}
+		end := stringLiteralEnd(runes, i)
+		if end < 0 {
+			return errors.Errorf("%v: Unterminated value of label %q", funcInfo(), label)
+		}
+		matchers++
+
+		i = skipSpaces(runes, end+1)
+		if i < len(runes) && runes[i] == ',' {
+			i++
+			continue
+		}
+		if i < len(runes) && runes[i] == '}' {
+			i++
+			break
+		}
+		return errors.Errorf("%v: Expected ',' or '}' after value of label %q", funcInfo(), label)
+	}
+
+	if i = skipSpaces(runes, i); i != len(runes) {
+		return errors.Errorf("%v: Unexpected %q after selector", funcInfo(), runes[i])
+	}
+
+	if !hasName && matchers == 0 {
+		return errors.Errorf("%v: Selector must contain metric name or at least one matcher", funcInfo())
+	}
+
+	return nil
+}
+
+// validateMatchers validates each series selector, returned error names the invalid one
+func validateMatchers(matches []string) error {
+	for _, match := range matches {
+		if err := ValidateMatcher(match); err != nil {
+			return errors.Wrapf(err, "%v: matcher %q validation failed", funcInfo(), match)
+		}
+	}
+
+	return nil
+}
+
+func skipSpaces(runes []rune, i int) int {
+	for i < len(runes) && unicode.IsSpace(runes[i]) {
+		i++
+	}
+
+	return i
+}
+
+// isNameRune reports whether rune is valid in metric name (colons allowed) or label name
+func isNameRune(r rune, first, metric bool) bool {
+	switch {
+	case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		return true
+	case r == ':':
+		return metric
+	case r >= '0' && r <= '9':
+		return !first
+	default:
+		return false
+	}
+}
+
+// matchOperatorLen returns length of label matching operator at position i, zero when there is none
+func matchOperatorLen(runes []rune, i int) int {
+	if i >= len(runes) {
+		return 0
+	}
+
+	switch runes[i] {
+	case '=':
+		if i+1 < len(runes) && runes[i+1] == '~' {
+			return 2
+		}
+		return 1
+	case '!':
+		if i+1 < len(runes) && (runes[i+1] == '=' || runes[i+1] == '~') {
+			return 2
+		}
+	}
+
+	return 0
+}
+
+func isQuote(r rune) bool {
+	return r == '"' || r == '\'' || r == '`'
+}
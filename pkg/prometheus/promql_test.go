@@ -0,0 +1,185 @@
+package prometheus
+
+import (
+	"testing"
+)
+
+func TestEscapeLabelValue(t *testing.T) {
+	tests := []struct {
+		name string
+		v    string
+		want string
+	}{
+		{
+			name: "Test EscapeLabelValue plain",
+			v:    "api",
+			want: "api",
+		},
+		{
+			name: "Test EscapeLabelValue quote",
+			v:    `say "hi"`,
+			want: `say \"hi\"`,
+		},
+		{
+			name: "Test EscapeLabelValue backslash",
+			v:    `C:\path`,
+			want: `C:\\path`,
+		},
+		{
+			name: "Test EscapeLabelValue newline",
+			v:    "line1\nline2",
+			want: `line1\nline2`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EscapeLabelValue(tt.v); got != tt.want {
+				t.Errorf("EscapeLabelValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelector(t *testing.T) {
+	type args struct {
+		metric string
+		labels map[string]string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Test Selector metric only",
+			args: args{metric: "up"},
+			want: "up",
+		},
+		{
+			name: "Test Selector escaped labels",
+			args: args{metric: "up", labels: map[string]string{"job": `a"b`, "path": `C:\`}},
+			want: `up{job="a\"b",path="C:\\"}`,
+		},
+		{
+			name: "Test Selector labels only",
+			args: args{labels: map[string]string{"job": "api"}},
+			want: `{job="api"}`,
+		},
+		{
+			name:    "Test Selector empty",
+			args:    args{labels: map[string]string{}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Selector(tt.args.metric, tt.args.labels)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Selector() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Selector() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "Test CanonicalizeQuery compact",
+			query: "rate(x[5m])",
+			want:  "rate(x[5m])",
+		},
+		{
+			name:  "Test CanonicalizeQuery whitespace variant",
+			query: " rate( x [5m] ) ",
+			want:  "rate(x[5m])",
+		},
+		{
+			name:  "Test CanonicalizeQuery keywords",
+			query: "sum  by (job)\n\t(rate(http_requests_total{code=~\"5..\"} [5m]))  and  on(job) up",
+			want:  `sum by(job)(rate(http_requests_total{code=~"5.."}[5m]))and on(job)up`,
+		},
+		{
+			name:  "Test CanonicalizeQuery string literals untouched",
+			query: `up{job = "a  b", path=~'x\' y'}`,
+			want:  `up{job="a  b",path=~'x\' y'}`,
+		},
+		{
+			name:  "Test CanonicalizeQuery comments dropped",
+			query: "up # all targets\n  == 1",
+			want:  "up==1",
+		},
+		{
+			name:    "Test CanonicalizeQuery unterminated string",
+			query:   `up{job="api}`,
+			wantErr: true,
+		},
+		{
+			name:    "Test CanonicalizeQuery unbalanced",
+			query:   "rate(x[5m)",
+			wantErr: true,
+		},
+		{
+			name:    "Test CanonicalizeQuery unclosed",
+			query:   "sum(rate(x[5m])",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalizeQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CanonicalizeQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CanonicalizeQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		wantErr  bool
+	}{
+		{name: "Test ValidateMatcher metric name", selector: "up"},
+		{name: "Test ValidateMatcher recording rule name", selector: "job:http_requests:rate5m"},
+		{name: "Test ValidateMatcher metric and matchers", selector: `up{job="prometheus", instance!~"localhost:.*"}`},
+		{name: "Test ValidateMatcher matchers only", selector: `{__name__=~"http_.*",code!="200"}`},
+		{name: "Test ValidateMatcher trailing comma", selector: `up{job="prometheus",}`},
+		{name: "Test ValidateMatcher single and backtick quotes", selector: "up{job='node',path=~`/api/.*`}"},
+		{name: "Test ValidateMatcher escaped quote", selector: `up{msg="say \"hi\""}`},
+		{name: "Test ValidateMatcher empty braces with name", selector: "up{}"},
+		{name: "Test ValidateMatcher missing value", selector: `{job=}`, wantErr: true},
+		{name: "Test ValidateMatcher unquoted value", selector: `up{job=prometheus}`, wantErr: true},
+		{name: "Test ValidateMatcher unterminated value", selector: `up{job="prometheus}`, wantErr: true},
+		{name: "Test ValidateMatcher unclosed brace", selector: `up{job="prometheus"`, wantErr: true},
+		{name: "Test ValidateMatcher invalid operator", selector: `up{job=="prometheus"}`, wantErr: true},
+		{name: "Test ValidateMatcher missing operator", selector: `up{job "prometheus"}`, wantErr: true},
+		{name: "Test ValidateMatcher missing comma", selector: `up{job="a" instance="b"}`, wantErr: true},
+		{name: "Test ValidateMatcher label starting with digit", selector: `up{1job="a"}`, wantErr: true},
+		{name: "Test ValidateMatcher empty", selector: " ", wantErr: true},
+		{name: "Test ValidateMatcher empty braces", selector: "{}", wantErr: true},
+		{name: "Test ValidateMatcher trailing garbage", selector: `up{job="a"} or vector(1)`, wantErr: true},
+		{name: "Test ValidateMatcher function call", selector: "rate(up[5m])", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateMatcher(tt.selector); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMatcher(%q) error = %v, wantErr %v", tt.selector, err, tt.wantErr)
+			}
+		})
+	}
+}
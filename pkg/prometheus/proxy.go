@@ -0,0 +1,86 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// QueryRangeProxy Prometheus query range writes upstream 'data.result' array to w without decoding samples,
+// e.g. for pass-through servers. Response goes through the same retries, WithResponseTransformer and
+// WithDataKeyPath as other queries and is re-issued with suggested step if enabled by WithFollowStepSuggestion.
+// It is buffered until its status is validated, so nothing is written on failure. Range cache of WithRangeCache
+// is not used as raw result is not cached, and WithQueryStats is ignored as only result array is written.
+// param: query - Prometheus query string
+// param: start - start time of range interval
+// param: end   - end time of range interval
+// param: step  - sampling interval
+// param: w     - writer receiving result array bytes exactly as sent by server
+func (m *Client) QueryRangeProxy(query string, start, end time.Time, step time.Duration, w io.Writer) error {
+	if err := m.checkStep(start, end, step); err != nil {
+		return errors.Wrapf(err, "%v: step check failed", funcInfo())
+	}
+
+	err := m.queryRangeProxy(query, start, end, shortDur(step), w)
+	if err != nil && m.followStepSuggestion {
+		if suggested, ok := suggestedStep(err, start, end); ok && suggested > step {
+			m.logger.Warn("Prometheus range query rejected, retrying with suggested step",
+				"step", step, "suggestedStep", suggested)
+			err = m.queryRangeProxy(query, start, end, shortDur(suggested), w)
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "%v: query range failed", funcInfo())
+	}
+
+	return nil
+}
+
+// queryRangeProxy runs range query with step formatted as it is sent and writes result array to w
+func (m *Client) queryRangeProxy(query string, start, end time.Time, step string, w io.Writer) error {
+	prometheusRequest := m.queryRangeURL(query, start, end, step)
+
+	m.logger.Debug("Prometheus request", "query", prometheusRequest)
+
+	return m.queryData(context.Background(), prometheusRequest, func(data []byte) error {
+		result, err := proxyResult(data)
+		if err != nil {
+			return errors.Wrapf(err, "%v: parsing response failed", funcInfo())
+		}
+
+		if _, err := w.Write(result); err != nil {
+			return errors.Wrapf(err, "%v: writing result failed", funcInfo())
+		}
+
+		return nil
+	})
+}
+
+// proxyResult returns 'data.result' of response with status "success" as sent by server
+func proxyResult(data []byte) ([]byte, error) {
+	if err := parseAPIError(data); err != nil {
+		return nil, errors.Wrapf(err, "%v: query failed", funcInfo())
+	}
+
+	var envelope struct {
+		Status string `json:"status"`
+		Data   struct {
+			Result json.RawMessage `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, errors.Wrapf(err, "%v: response unmarshal failed", funcInfo())
+	}
+
+	if envelope.Status != statusSuccessResponse {
+		return nil, errors.Errorf("%v: Unexpected response status %q", funcInfo(), envelope.Status)
+	}
+	if envelope.Data.Result == nil {
+		return nil, errors.Errorf("%v: Result parsing failed", funcInfo())
+	}
+
+	return envelope.Data.Result, nil
+}
@@ -0,0 +1,158 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// errAny matches any error in tests expecting failure without specific cause
+var errAny = errors.New("any error")
+
+func TestClient_QueryRangeProxy(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Unix(1500000000, 0)
+	result := `[{"metric":{"__name__":"up"},"values":[[1500000000,"1"], [1500000060,"1"]]}]`
+
+	tests := []struct {
+		name     string
+		response string
+		status   int
+		opts     []Option
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "Test QueryRangeProxy unicorn path",
+			response: `{"status":"success","data":{"resultType":"matrix","result":` + result + `}}`,
+			want:     result,
+		},
+		{
+			name:     "Test QueryRangeProxy error status",
+			response: `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			status:   http.StatusBadRequest,
+			wantErr:  errAny,
+		},
+		{
+			name:     "Test QueryRangeProxy missing status",
+			response: `{"data":{"resultType":"matrix","result":` + result + `}}`,
+			wantErr:  errAny,
+		},
+		{
+			name:     "Test QueryRangeProxy missing result",
+			response: `{"status":"success","data":{"resultType":"matrix"}}`,
+			wantErr:  errAny,
+		},
+		{
+			name:    "Test QueryRangeProxy not supported",
+			status:  http.StatusNotFound,
+			wantErr: ErrNotSupported,
+		},
+		{
+			name:     "Test QueryRangeProxy data key path",
+			response: `{"payload":{"status":"success","data":{"resultType":"matrix","result":` + result + `}}}`,
+			opts:     []Option{WithDataKeyPath([]string{"payload"})},
+			want:     result,
+		},
+		{
+			name:     "Test QueryRangeProxy response transformer",
+			response: `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+			opts: []Option{WithResponseTransformer(func(data []byte) ([]byte, error) {
+				return bytes.Replace(data, []byte("[]"), []byte(result), 1), nil
+			})},
+			want: result,
+		},
+		{
+			name:     "Test QueryRangeProxy server failure",
+			response: "upstream failure",
+			status:   http.StatusBadGateway,
+			wantErr:  errAny,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query_range", "9090", func(w http.ResponseWriter, r *http.Request) {
+			if tt.status != 0 {
+				w.WriteHeader(tt.status)
+			}
+			fmt.Fprint(w, tt.response)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewClient("http", "127.0.0.1", "9090", append([]Option{WithLogger(logger)}, tt.opts...)...)
+
+			var got bytes.Buffer
+			err := m.QueryRangeProxy("up", start, start.Add(time.Hour), time.Minute, &got)
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("Client.QueryRangeProxy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil && tt.wantErr != errAny && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Client.QueryRangeProxy() error = %v, want %v", err, tt.wantErr)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Client.QueryRangeProxy() wrote %q, want %q", got.String(), tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_QueryRangeProxy_retry(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	result := `[{"metric":{"__name__":"up"},"values":[[1500000000,"1"]]}]`
+
+	requests := 0
+	httpServer := startHTTPServer("/api/v1/query_range", "9090", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"matrix","result":`+result+`}}`)
+	})
+	defer httpServer.Shutdown(context.Background())
+
+	m := NewClient("http", "127.0.0.1", "9090", WithRetry(2, time.Millisecond))
+
+	var got bytes.Buffer
+	if err := m.QueryRangeProxy("up", start, start.Add(time.Hour), time.Minute, &got); err != nil {
+		t.Fatalf("Client.QueryRangeProxy() error = %v", err)
+	}
+	if got.String() != result || requests != 2 {
+		t.Errorf("Client.QueryRangeProxy() wrote %q after %v requests, want %q after 2", got.String(), requests, result)
+	}
+}
+
+func TestClient_QueryRangeProxy_followStepSuggestion(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	result := `[{"metric":{"__name__":"up"},"values":[[1500000000,"1"]]}]`
+
+	var steps []string
+	httpServer := startHTTPServer("/api/v1/query_range", "9090", func(w http.ResponseWriter, r *http.Request) {
+		steps = append(steps, r.URL.Query().Get("step"))
+		if r.URL.Query().Get("step") != "1m" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"query too dense, try step=1m"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"matrix","result":`+result+`}}`)
+	})
+	defer httpServer.Shutdown(context.Background())
+
+	m := NewClient("http", "127.0.0.1", "9090", WithFollowStepSuggestion())
+
+	var got bytes.Buffer
+	if err := m.QueryRangeProxy("up", start, start.Add(time.Hour), time.Second, &got); err != nil {
+		t.Fatalf("Client.QueryRangeProxy() error = %v", err)
+	}
+	if got.String() != result || fmt.Sprint(steps) != "[1s 1m]" {
+		t.Errorf("Client.QueryRangeProxy() wrote %q with steps %v, want %q with steps [1s 1m]", got.String(), steps, result)
+	}
+}
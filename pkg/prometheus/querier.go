@@ -0,0 +1,35 @@
+package prometheus
+
+import (
+	"context"
+	"time"
+)
+
+// Querier query methods of Client, consumers can depend on it instead of *Client and substitute fake in tests
+type Querier interface {
+	QueryRequest(query string) ([]byte, string, error)
+	QueryRequestWithContext(ctx context.Context, query string) ([]byte, string, error)
+	QueryAt(query string, evalTime time.Time) (*QueryResult, error)
+	QueryWithWarnings(query string) ([]byte, string, []string, error)
+	QueryRangeRequest(query string, start, end time.Time, step time.Duration) ([]byte, string, error)
+	QueryRangeRequestWithContext(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]byte, string, error)
+	QueryRangeWithWarnings(query string, start, end time.Time, step time.Duration) ([]byte, string, []string, error)
+}
+
+// MetadataQuerier series, label, rule, alert, target and metadata methods of Client,
+// consumers can depend on it instead of *Client and substitute fake in tests
+type MetadataQuerier interface {
+	Series(matches []string, start, end time.Time) ([]map[string]string, error)
+	SeriesStream(matchers []string, start, end time.Time, fn func(map[string]string) error) error
+	Labels(start, end time.Time, matches []string) ([]string, error)
+	LabelValues(label string, start, end time.Time, matches []string) ([]string, error)
+	Rules(ruleType string, excludeAlerts bool) ([]RuleGroup, error)
+	Alerts() ([]Alert, error)
+	Targets(state string) (*Targets, error)
+	Metadata(metric string, limit int) (map[string][]Meta, error)
+}
+
+var (
+	_ Querier         = (*Client)(nil)
+	_ MetadataQuerier = (*Client)(nil)
+)
@@ -0,0 +1,70 @@
+package prometheus
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeQuerier Querier answering every query with fixed vector
+type fakeQuerier struct {
+	queries []string
+}
+
+func (f *fakeQuerier) QueryRequest(query string) ([]byte, string, error) {
+	return f.QueryRequestWithContext(context.Background(), query)
+}
+
+func (f *fakeQuerier) QueryRequestWithContext(ctx context.Context, query string) ([]byte, string, error) {
+	f.queries = append(f.queries, query)
+	return []byte(`[{"metric":{},"value":[1500000000,"1"]}]`), ResultTypeVector, nil
+}
+
+func (f *fakeQuerier) QueryAt(query string, evalTime time.Time) (*QueryResult, error) {
+	result, resultType, err := f.QueryRequest(query)
+	return &QueryResult{Result: result, ResultType: resultType, EvalTime: evalTime}, err
+}
+
+func (f *fakeQuerier) QueryWithWarnings(query string) ([]byte, string, []string, error) {
+	result, resultType, err := f.QueryRequest(query)
+	return result, resultType, nil, err
+}
+
+func (f *fakeQuerier) QueryRangeRequest(query string, start, end time.Time, step time.Duration) ([]byte, string, error) {
+	return f.QueryRangeRequestWithContext(context.Background(), query, start, end, step)
+}
+
+func (f *fakeQuerier) QueryRangeRequestWithContext(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]byte, string, error) {
+	f.queries = append(f.queries, query)
+	return []byte(`[{"metric":{},"values":[[1500000000,"1"]]}]`), ResultTypeMatrix, nil
+}
+
+func (f *fakeQuerier) QueryRangeWithWarnings(query string, start, end time.Time, step time.Duration) ([]byte, string, []string, error) {
+	result, resultType, err := f.QueryRangeRequest(query, start, end, step)
+	return result, resultType, nil, err
+}
+
+func TestQuerier_fake(t *testing.T) {
+	latest := func(q Querier, query string) ([]Sample, error) {
+		result, resultType, err := q.QueryRequest(query)
+		if err != nil {
+			return nil, err
+		}
+		return ParseVector(result, resultType)
+	}
+
+	fake := &fakeQuerier{}
+	got, err := latest(fake, "up")
+	if err != nil {
+		t.Fatalf("latest() error = %v", err)
+	}
+
+	want := []Sample{{Metric: map[string]string{}, Value: 1, Timestamp: time.Unix(1500000000, 0)}}
+	if len(got) != 1 || got[0].Value != want[0].Value || !got[0].Timestamp.Equal(want[0].Timestamp) {
+		t.Errorf("latest() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(fake.queries, []string{"up"}) {
+		t.Errorf("fakeQuerier queries = %v, want [up]", fake.queries)
+	}
+}
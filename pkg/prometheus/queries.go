@@ -0,0 +1,70 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ActiveQuery query currently being evaluated by server
+type ActiveQuery struct {
+	ID         string
+	Query      string
+	RemoteAddr string
+	Duration   time.Duration
+}
+
+// UnmarshalJSON decodes active query, duration is accepted as Go duration string (e.g. "3.183s")
+func (q *ActiveQuery) UnmarshalJSON(data []byte) error {
+	var query struct {
+		ID         string `json:"id"`
+		Query      string `json:"query"`
+		RemoteAddr string `json:"remote_addr"`
+		Duration   string `json:"duration"`
+	}
+
+	err := json.Unmarshal(data, &query)
+	if err != nil {
+		return errors.Wrapf(err, "%v: active query unmarshal failed", funcInfo())
+	}
+
+	*q = ActiveQuery{ID: query.ID, Query: query.Query, RemoteAddr: query.RemoteAddr}
+	if query.Duration != "" {
+		q.Duration, err = time.ParseDuration(query.Duration)
+		if err != nil {
+			return errors.Wrapf(err, "%v: active query duration parsing failed", funcInfo())
+		}
+	}
+
+	return nil
+}
+
+// ActiveQueries returns queries currently being evaluated. Upstream Prometheus tracks active queries
+// only in its query log file, the status/active_queries endpoint is exposed by compatible servers
+// such as VictoriaMetrics.
+// result: []ActiveQuery - active queries, error wrapping ErrNotSupported when server does not expose them
+func (m *Client) ActiveQueries() ([]ActiveQuery, error) {
+	data, err := m.getData(context.Background(), "status/active_queries", nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: active queries request failed", funcInfo())
+	}
+
+	var queries []ActiveQuery
+
+	err = json.Unmarshal(data, &queries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: active queries unmarshal failed", funcInfo())
+	}
+
+	return queries, nil
+}
+
+// CancelQuery cancels active query by ID. Prometheus has no HTTP API cancelling single query,
+// queries can only be bounded by WithQueryTimeout or request context, so it always fails
+// with ErrNotSupported without contacting server.
+// param: id - ID of query as reported by ActiveQueries
+func (m *Client) CancelQuery(id string) error {
+	return errors.Wrapf(ErrNotSupported, "%v: cancelling query %q", funcInfo(), id)
+}
@@ -0,0 +1,85 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var activeQueriesResponse = `{"status":"ok","data":[` +
+	`{"duration":"3.183s","id":"17F24E8F09E8BF96","remote_addr":"127.0.0.1:56928","query":"sum(rate(http_requests_total[5m]))",` +
+	`"start":1500000000000,"end":1500003600000,"step":15000},` +
+	`{"duration":"0.012s","id":"17F24E8F09E8BF97","remote_addr":"127.0.0.1:56930","query":"up"}]}`
+
+func TestClient_ActiveQueries(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name      string
+		m         *Client
+		handler   func(w http.ResponseWriter, r *http.Request)
+		want      []ActiveQuery
+		wantCause error
+		wantErr   bool
+	}{
+		{
+			name: "Test ActiveQueries unicorn path",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, activeQueriesResponse)
+			},
+			want: []ActiveQuery{
+				{ID: "17F24E8F09E8BF96", Query: "sum(rate(http_requests_total[5m]))", RemoteAddr: "127.0.0.1:56928", Duration: 3183 * time.Millisecond},
+				{ID: "17F24E8F09E8BF97", Query: "up", RemoteAddr: "127.0.0.1:56930", Duration: 12 * time.Millisecond},
+			},
+		},
+		{
+			name:      "Test ActiveQueries not supported",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler:   http.NotFound,
+			wantCause: ErrNotSupported,
+			wantErr:   true,
+		},
+		{
+			name: "Test ActiveQueries invalid duration",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"status":"ok","data":[{"id":"1","query":"up","duration":"long"}]}`)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/status/active_queries", "9090", tt.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.ActiveQueries()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.ActiveQueries() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantCause != nil && errors.Cause(err) != tt.wantCause {
+				t.Errorf("Client.ActiveQueries() error = %v, want %v", err, tt.wantCause)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.ActiveQueries() got = %+v, want %+v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_CancelQuery(t *testing.T) {
+	m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(zap.NewExample()), timeout: time.Second * 30}
+
+	if err := m.CancelQuery("42"); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Client.CancelQuery() error = %v, want %v", err, ErrNotSupported)
+	}
+}
@@ -0,0 +1,31 @@
+package prometheus
+
+// ClientRate computes per-second rate of counter between consecutive samples of raw range vector series,
+// counter resets (value decreasing) are handled as in Prometheus by treating the value after reset as the increase.
+// Unlike server-side rate() it does not extrapolate to window boundaries, is not averaged over range window
+// but over single sample interval, and ignores staleness markers, so it matches rate() only for evenly
+// scraped counters queried with window equal to scrape interval.
+// param: stream - raw counter samples ordered by time
+// result: []SamplePair - one rate per sample interval timestamped at its end, pairs with non-increasing
+// timestamps are skipped, fewer than two samples give empty result
+func ClientRate(stream SampleStream) []SamplePair {
+	rates := []SamplePair{}
+
+	for i := 1; i < len(stream.Values); i++ {
+		prev, curr := stream.Values[i-1], stream.Values[i]
+
+		interval := curr.Timestamp.Sub(prev.Timestamp).Seconds()
+		if interval <= 0 {
+			continue
+		}
+
+		increase := curr.Value - prev.Value
+		if increase < 0 {
+			increase = curr.Value
+		}
+
+		rates = append(rates, SamplePair{Timestamp: curr.Timestamp, Value: increase / interval})
+	}
+
+	return rates
+}
@@ -0,0 +1,54 @@
+package prometheus
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClientRate(t *testing.T) {
+	start := time.Unix(1554000000, 0)
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+
+	tests := []struct {
+		name   string
+		stream SampleStream
+		want   []SamplePair
+	}{
+		{
+			name: "Test ClientRate steady counter",
+			stream: SampleStream{Values: []SamplePair{
+				{Timestamp: at(0), Value: 100}, {Timestamp: at(10), Value: 150}, {Timestamp: at(20), Value: 250},
+			}},
+			want: []SamplePair{{Timestamp: at(10), Value: 5}, {Timestamp: at(20), Value: 10}},
+		},
+		{
+			name: "Test ClientRate counter reset",
+			stream: SampleStream{Values: []SamplePair{
+				{Timestamp: at(0), Value: 100}, {Timestamp: at(10), Value: 200}, {Timestamp: at(20), Value: 30}, {Timestamp: at(30), Value: 80},
+			}},
+			want: []SamplePair{{Timestamp: at(10), Value: 10}, {Timestamp: at(20), Value: 3}, {Timestamp: at(30), Value: 5}},
+		},
+		{
+			name: "Test ClientRate duplicate timestamp",
+			stream: SampleStream{Values: []SamplePair{
+				{Timestamp: at(0), Value: 1}, {Timestamp: at(0), Value: 1}, {Timestamp: at(5), Value: 11},
+			}},
+			want: []SamplePair{{Timestamp: at(5), Value: 2}},
+		},
+		{
+			name:   "Test ClientRate single sample",
+			stream: SampleStream{Values: []SamplePair{{Timestamp: at(0), Value: 1}}},
+			want:   []SamplePair{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClientRate(tt.stream); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ClientRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
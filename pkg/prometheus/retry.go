@@ -0,0 +1,93 @@
+package prometheus
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WithRetry retries queries failed on connection errors, timeouts of single attempt or 5xx responses up to maxAttempts times in total,
+// waiting exponentially growing backoff with jitter between attempts. 4xx responses and parse errors are
+// deterministic and never retried, no attempt is started when its backoff would exceed context deadline.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(args *Client) {
+		args.retryAttempts = maxAttempts
+		args.retryBackoff = backoff
+	}
+}
+
+// retry calls fn until it succeeds, fails with non-retryable error or attempts configured by WithRetry are exhausted
+func (m *Client) retry(ctx context.Context, fn func() error) error {
+	if m.retryAttempts <= 1 {
+		return fn()
+	}
+
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		if attempt >= m.retryAttempts || !retryableError(ctx, err) {
+			break
+		}
+
+		delay := retryDelay(m.retryBackoff, attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			break
+		}
+
+		m.logger.Warn("Prometheus request failed, retrying", "attempt", attempt, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrapf(err, "%v: request failed after %v attempts", funcInfo(), attempt)
+		case <-timer.C:
+		}
+	}
+
+	return errors.Wrapf(err, "%v: request failed after %v attempts", funcInfo(), attempt)
+}
+
+// retryableError reports whether request failure is transient, i.e. connection error, per-attempt timeout
+// or 5xx response, nothing is retried once context of caller is done
+func retryableError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	switch cause := errors.Cause(err).(type) {
+	case *StatusError:
+		return cause.StatusCode >= http.StatusInternalServerError
+	case *APIError:
+		return cause.StatusCode >= http.StatusInternalServerError
+	case *url.Error:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay returns backoff doubled for every previous retry, randomized into [delay/2, delay]
+func retryDelay(backoff time.Duration, attempt int) time.Duration {
+	if backoff <= 0 {
+		return 0
+	}
+
+	delay := backoff << uint(attempt-1)
+	if delay <= 0 || delay > time.Hour {
+		delay = time.Hour
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
@@ -0,0 +1,161 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// failingHandler responds with status for the first failures requests and with unicorn response afterwards
+func failingHandler(status, failures int, requests *int) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if *requests <= failures {
+			w.WriteHeader(status)
+			fmt.Fprint(w, "failure")
+			return
+		}
+		fmt.Fprint(w, string(unicornResponse))
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name         string
+		status       int
+		failures     int
+		attempts     int
+		timeout      time.Duration
+		wantRequests int
+		wantErr      bool
+	}{
+		{
+			name:         "Test WithRetry recovers after 5xx",
+			status:       http.StatusServiceUnavailable,
+			failures:     2,
+			attempts:     3,
+			wantRequests: 3,
+		},
+		{
+			name:         "Test WithRetry attempts exhausted",
+			status:       http.StatusInternalServerError,
+			failures:     5,
+			attempts:     3,
+			wantRequests: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "Test WithRetry 4xx not retried",
+			status:       http.StatusBadRequest,
+			failures:     5,
+			attempts:     3,
+			wantRequests: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "Test WithRetry parse error not retried",
+			status:       http.StatusOK,
+			failures:     5,
+			attempts:     3,
+			wantRequests: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "Test WithRetry context deadline",
+			status:       http.StatusServiceUnavailable,
+			failures:     5,
+			attempts:     3,
+			timeout:      time.Millisecond,
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		requests := 0
+		httpServer := startHTTPServer("/api/v1/query", "9090", failingHandler(tt.status, tt.failures, &requests))
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+			backoff := time.Millisecond * 10
+			if tt.timeout > 0 {
+				backoff = time.Second
+			}
+			WithRetry(tt.attempts, backoff)(m)
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, time.Millisecond*200)
+				defer cancel()
+			}
+
+			_, _, err := m.QueryRequestWithContext(ctx, "up")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRequestWithContext() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("Client.QueryRequestWithContext() requests = %v, want %v", requests, tt.wantRequests)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), fmt.Sprintf("after %v attempts", tt.wantRequests)) {
+				t.Errorf("Client.QueryRequestWithContext() error = %v, want attempt count %v", err, tt.wantRequests)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestWithRetry_timeout(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	var requests int32
+	httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			time.Sleep(time.Millisecond * 300)
+		}
+		unicornHandler(w, r)
+	})
+	defer httpServer.Shutdown(context.Background())
+
+	m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Millisecond * 100}
+	WithRetry(2, time.Millisecond)(m)
+
+	if _, _, err := m.QueryRequest("up"); err != nil {
+		t.Errorf("Client.QueryRequest() error = %v, want retried timeout", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("Client.QueryRequest() requests = %v, want %v", got, 2)
+	}
+}
+
+func TestWithRetry_connectionError(t *testing.T) {
+	m := &Client{protocol: "http", address: "127.0.0.1", port: "9099", logger: NewZapLogger(zap.NewExample()), timeout: time.Second * 30}
+	WithRetry(2, time.Millisecond)(m)
+
+	_, _, err := m.QueryRequest("up")
+	if err == nil || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Errorf("Client.QueryRequest() error = %v, want retried connection error", err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempt := 1; attempt <= 4; attempt++ {
+		max := time.Millisecond * 100 << uint(attempt-1)
+		got := retryDelay(time.Millisecond*100, attempt)
+		if got < max/2 || got > max {
+			t.Errorf("retryDelay() attempt %v = %v, want between %v and %v", attempt, got, max/2, max)
+		}
+	}
+	if got := retryDelay(0, 3); got != 0 {
+		t.Errorf("retryDelay() zero backoff = %v, want 0", got)
+	}
+}
@@ -0,0 +1,124 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// RuleTypeAlert alerting rule type
+	RuleTypeAlert = "alert"
+	// RuleTypeRecord recording rule type
+	RuleTypeRecord = "record"
+)
+
+// RuleGroup Prometheus rule group
+type RuleGroup struct {
+	Name  string `json:"name"`
+	File  string `json:"file"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule Prometheus alerting or recording rule, alerting rule specific fields are empty for recording rules
+type Rule struct {
+	Name           string            `json:"name"`
+	Query          string            `json:"query"`
+	Type           string            `json:"type"`
+	Health         string            `json:"health"`
+	Labels         map[string]string `json:"labels"`
+	LastError      string            `json:"lastError"`
+	EvaluationTime time.Duration     `json:"-"`
+	LastEvaluation time.Time         `json:"-"`
+
+	// Duration 'for' clause of alerting rule
+	Duration    time.Duration     `json:"-"`
+	Annotations map[string]string `json:"annotations"`
+	State       string            `json:"state"`
+	Alerts      []Alert           `json:"alerts"`
+}
+
+// UnmarshalJSON decodes rule, converting evaluationTime and duration in float seconds to time.Duration
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	type plainRule Rule
+
+	var rule struct {
+		plainRule
+		EvaluationTime float64    `json:"evaluationTime"`
+		LastEvaluation *time.Time `json:"lastEvaluation"`
+		Duration       float64    `json:"duration"`
+	}
+
+	err := json.Unmarshal(data, &rule)
+	if err != nil {
+		return errors.Wrapf(err, "%v: rule unmarshal failed", funcInfo())
+	}
+
+	*r = Rule(rule.plainRule)
+	r.EvaluationTime = secondsToDuration(rule.EvaluationTime)
+	r.Duration = secondsToDuration(rule.Duration)
+	if rule.LastEvaluation != nil {
+		r.LastEvaluation = *rule.LastEvaluation
+	}
+
+	return nil
+}
+
+// Rules returns Prometheus rule groups
+// param: ruleType      - RuleTypeAlert or RuleTypeRecord filters rules server-side, empty string returns all rules
+// param: excludeAlerts - omits active alerts of alerting rules for lighter payload
+// result: []RuleGroup - rule groups with contained rules
+func (m *Client) Rules(ruleType string, excludeAlerts bool) ([]RuleGroup, error) {
+	params := url.Values{}
+
+	if excludeAlerts {
+		params.Set("exclude_alerts", "true")
+	}
+
+	switch ruleType {
+	case "":
+	case RuleTypeAlert, RuleTypeRecord:
+		params.Set("type", ruleType)
+	default:
+		return nil, errors.Errorf("%v: Unknown rule type %v", funcInfo(), ruleType)
+	}
+
+	data, err := m.getData(context.Background(), "rules", params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: rules request failed", funcInfo())
+	}
+
+	var rules struct {
+		Groups []RuleGroup `json:"groups"`
+	}
+
+	err = json.Unmarshal(data, &rules)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: rules unmarshal failed", funcInfo())
+	}
+
+	return rules.Groups, nil
+}
+
+// FailingRules returns Prometheus rules whose last evaluation failed
+// result: []Rule - rules with non-empty LastError across all rule groups
+func (m *Client) FailingRules() ([]Rule, error) {
+	groups, err := m.Rules("", true)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: rules request failed", funcInfo())
+	}
+
+	var failing []Rule
+	for _, group := range groups {
+		for _, rule := range group.Rules {
+			if rule.LastError != "" {
+				failing = append(failing, rule)
+			}
+		}
+	}
+
+	return failing, nil
+}
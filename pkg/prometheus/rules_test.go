@@ -0,0 +1,261 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	alertRulesResponse = `{"status":"success","data":{"groups":[{"name":"example","file":"rules.yml","rules":[` +
+		`{"name":"HighLatency","query":"job:latency:avg > 1","type":"alerting","health":"ok","labels":{"severity":"page"}}]}]}}`
+	recordRulesResponse = `{"status":"success","data":{"groups":[{"name":"example","file":"rules.yml","rules":[` +
+		`{"name":"job:latency:avg","query":"avg by(job) (latency)","type":"recording","health":"ok"}]}]}}`
+	allRulesResponse = `{"status":"success","data":{"groups":[{"name":"example","file":"rules.yml","rules":[` +
+		`{"name":"HighLatency","query":"job:latency:avg > 1","type":"alerting","health":"ok","labels":{"severity":"page"}},` +
+		`{"name":"job:latency:avg","query":"avg by(job) (latency)","type":"recording","health":"ok"}]}]}}`
+
+	rulesHandler = func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("type") {
+		case RuleTypeAlert:
+			fmt.Fprint(w, alertRulesResponse)
+		case RuleTypeRecord:
+			fmt.Fprint(w, recordRulesResponse)
+		default:
+			fmt.Fprint(w, allRulesResponse)
+		}
+	}
+)
+
+func TestClient_Rules(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	alertRule := Rule{Name: "HighLatency", Query: "job:latency:avg > 1", Type: "alerting", Health: "ok", Labels: map[string]string{"severity": "page"}}
+	recordRule := Rule{Name: "job:latency:avg", Query: "avg by(job) (latency)", Type: "recording", Health: "ok"}
+
+	type args struct {
+		ruleType      string
+		excludeAlerts bool
+		handler       func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name              string
+		m                 *Client
+		args              args
+		want              []RuleGroup
+		wantType          string
+		wantExcludeAlerts string
+		wantErr           bool
+	}{
+		{
+			name:     "Test Rules unicorn path",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:     args{handler: rulesHandler},
+			want:     []RuleGroup{{Name: "example", File: "rules.yml", Rules: []Rule{alertRule, recordRule}}},
+			wantType: "",
+		},
+		{
+			name:     "Test Rules alert",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:     args{ruleType: RuleTypeAlert, handler: rulesHandler},
+			want:     []RuleGroup{{Name: "example", File: "rules.yml", Rules: []Rule{alertRule}}},
+			wantType: "alert",
+		},
+		{
+			name:     "Test Rules record",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:     args{ruleType: RuleTypeRecord, handler: rulesHandler},
+			want:     []RuleGroup{{Name: "example", File: "rules.yml", Rules: []Rule{recordRule}}},
+			wantType: "record",
+		},
+		{
+			name:              "Test Rules exclude alerts",
+			m:                 &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:              args{ruleType: RuleTypeAlert, excludeAlerts: true, handler: rulesHandler},
+			want:              []RuleGroup{{Name: "example", File: "rules.yml", Rules: []Rule{alertRule}}},
+			wantType:          "alert",
+			wantExcludeAlerts: "true",
+		},
+		{
+			name:    "Test Rules unknown type",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{ruleType: "unknown", handler: rulesHandler},
+			wantErr: true,
+		},
+		{
+			name:    "Test Rules data fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{handler: dataFailhandler},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		var gotType, gotExcludeAlerts string
+		httpServer := startHTTPServer("/api/v1/rules", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotType = r.URL.Query().Get("type")
+			gotExcludeAlerts = r.URL.Query().Get("exclude_alerts")
+			tt.args.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Rules(tt.args.ruleType, tt.args.excludeAlerts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Rules() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.Rules() got = %v, want %v", got, tt.want)
+			}
+			if gotType != tt.wantType {
+				t.Errorf("Client.Rules() type param = %v, want %v", gotType, tt.wantType)
+			}
+			if gotExcludeAlerts != tt.wantExcludeAlerts {
+				t.Errorf("Client.Rules() exclude_alerts param = %v, want %v", gotExcludeAlerts, tt.wantExcludeAlerts)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestRule_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Rule
+		wantErr bool
+	}{
+		{
+			name: "Test Rule UnmarshalJSON alerting",
+			data: `{"name":"HighLatency","query":"job:latency:avg > 1","type":"alerting","health":"ok","duration":600,` +
+				`"labels":{"severity":"page"},"annotations":{"summary":"High latency"},"state":"firing",` +
+				`"alerts":[{"labels":{"alertname":"HighLatency"},"state":"firing","activeAt":"2019-03-31T01:30:00Z","value":"1.5"}],` +
+				`"evaluationTime":0.002,"lastEvaluation":"2019-03-31T01:40:00Z"}`,
+			want: Rule{
+				Name:           "HighLatency",
+				Query:          "job:latency:avg > 1",
+				Type:           "alerting",
+				Health:         "ok",
+				Labels:         map[string]string{"severity": "page"},
+				EvaluationTime: 2 * time.Millisecond,
+				LastEvaluation: time.Date(2019, 3, 31, 1, 40, 0, 0, time.UTC),
+				Duration:       10 * time.Minute,
+				Annotations:    map[string]string{"summary": "High latency"},
+				State:          "firing",
+				Alerts: []Alert{{
+					Labels:   map[string]string{"alertname": "HighLatency"},
+					State:    "firing",
+					ActiveAt: time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC),
+					Value:    1.5,
+				}},
+			},
+		},
+		{
+			name: "Test Rule UnmarshalJSON recording",
+			data: `{"name":"job:latency:avg","query":"avg by(job) (latency)","type":"recording","health":"ok",` +
+				`"evaluationTime":0.0005,"lastEvaluation":"2019-03-31T01:40:00Z"}`,
+			want: Rule{
+				Name:           "job:latency:avg",
+				Query:          "avg by(job) (latency)",
+				Type:           "recording",
+				Health:         "ok",
+				EvaluationTime: 500 * time.Microsecond,
+				LastEvaluation: time.Date(2019, 3, 31, 1, 40, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "Test Rule UnmarshalJSON invalid duration",
+			data:    `{"name":"HighLatency","type":"alerting","duration":"10m"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Rule
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Rule.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			got.LastEvaluation = got.LastEvaluation.UTC()
+			for i := range got.Alerts {
+				got.Alerts[i].ActiveAt = got.Alerts[i].ActiveAt.UTC()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rule.UnmarshalJSON() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_FailingRules(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	mixedRulesResponse := `{"status":"success","data":{"groups":[` +
+		`{"name":"healthy","file":"rules.yml","rules":[` +
+		`{"name":"job:latency:avg","query":"avg by(job) (latency)","type":"recording","health":"ok","lastError":"","evaluationTime":0.0005}]},` +
+		`{"name":"broken","file":"rules.yml","rules":[` +
+		`{"name":"HighLatency","query":"job:latency:avg > 1","type":"alerting","health":"ok","evaluationTime":0.001},` +
+		`{"name":"job:errors:rate","query":"rate(errors[5m]) / on() group_left vector(0)","type":"recording","health":"err",` +
+		`"lastError":"many-to-many matching not allowed","evaluationTime":1.5}]}]}}`
+
+	tests := []struct {
+		name    string
+		m       *Client
+		handler func(w http.ResponseWriter, r *http.Request)
+		want    []Rule
+		wantErr bool
+	}{
+		{
+			name: "Test FailingRules unicorn path",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, mixedRulesResponse)
+			},
+			want: []Rule{{
+				Name:           "job:errors:rate",
+				Query:          "rate(errors[5m]) / on() group_left vector(0)",
+				Type:           "recording",
+				Health:         "err",
+				LastError:      "many-to-many matching not allowed",
+				EvaluationTime: 1500 * time.Millisecond,
+			}},
+		},
+		{
+			name:    "Test FailingRules all healthy",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: rulesHandler,
+		},
+		{
+			name:    "Test FailingRules data fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: dataFailhandler,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/rules", "9090", tt.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.FailingRules()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.FailingRules() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.FailingRules() got = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
@@ -0,0 +1,417 @@
+package prometheus
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ResultTypeVector Prometheus instant vector result type
+	ResultTypeVector = "vector"
+	// ResultTypeMatrix Prometheus range vector result type
+	ResultTypeMatrix = "matrix"
+	// ResultTypeScalar Prometheus scalar result type
+	ResultTypeScalar = "scalar"
+	// ResultTypeString Prometheus string result type
+	ResultTypeString = "string"
+	// ResultTypeUnknown reported for result type this client does not recognize, result is returned undecoded
+	ResultTypeUnknown = "unknown"
+)
+
+// knownResultType reports whether result type is decoded by this client, missing type is treated as known
+// so that malformed responses are still reported as errors
+func knownResultType(resultType string) bool {
+	switch resultType {
+	case "", ResultTypeVector, ResultTypeMatrix, ResultTypeScalar, ResultTypeString:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sample single sample of instant vector
+type Sample struct {
+	Metric    map[string]string
+	Value     float64
+	Timestamp time.Time
+}
+
+// SamplePair single timestamp/value pair of range vector series
+type SamplePair struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// SampleStream single series of range vector
+type SampleStream struct {
+	Metric map[string]string
+	Values []SamplePair
+}
+
+// ColumnarStream single series of range vector as index-aligned timestamp and value columns,
+// e.g. for zero-copy hand-over to numeric libraries
+type ColumnarStream struct {
+	Metric     map[string]string
+	Timestamps []time.Time
+	Values     []float64
+}
+
+// UnmarshalJSON decodes Prometheus [<unix_time>, "<value>"] pair
+func (p *SamplePair) UnmarshalJSON(data []byte) error {
+	var pair []interface{}
+
+	err := json.Unmarshal(data, &pair)
+	if err != nil {
+		return errors.Wrapf(err, "%v: sample pair unmarshal failed", funcInfo())
+	}
+
+	if len(pair) != 2 {
+		return errors.Errorf("%v: Sample pair has %v elements", funcInfo(), len(pair))
+	}
+
+	ts, ok := pair[0].(float64)
+	if !ok {
+		return errors.Errorf("%v: Sample timestamp parsing failed", funcInfo())
+	}
+
+	valueStr, ok := pair[1].(string)
+	if !ok {
+		return errors.Errorf("%v: Sample value parsing failed", funcInfo())
+	}
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return errors.Wrapf(err, "%v: sample value parsing failed", funcInfo())
+	}
+
+	p.Timestamp = unixToTime(ts)
+	p.Value = value
+
+	return nil
+}
+
+// QueryLatest Prometheus query returns single most recent sample
+// param: query - Prometheus query string
+// result: Sample - sample with the greatest timestamp, ties are broken by labels
+func (m *Client) QueryLatest(query string) (Sample, error) {
+	resp, resultType, err := m.QueryRequest(query)
+	if err != nil {
+		return Sample{}, errors.Wrapf(err, "%v: query request failed", funcInfo())
+	}
+
+	var samples []Sample
+
+	switch resultType {
+	case ResultTypeVector:
+		samples, err = decodeVector(resp, m.maxResultSeries)
+	case ResultTypeMatrix:
+		var streams []SampleStream
+		streams, err = decodeMatrix(resp, m.maxResultSeries)
+		samples = latestSamples(streams)
+	default:
+		return Sample{}, errors.Wrapf(ErrUnexpectedResultType, "%v: got %v", funcInfo(), resultType)
+	}
+	if err != nil {
+		return Sample{}, errors.Wrapf(err, "%v: result decoding failed", funcInfo())
+	}
+
+	if len(samples) == 0 {
+		return Sample{}, errors.Errorf("%v: Result is empty", funcInfo())
+	}
+
+	latest := samples[0]
+	for _, sample := range samples[1:] {
+		if sample.Timestamp.After(latest.Timestamp) ||
+			sample.Timestamp.Equal(latest.Timestamp) && labelsString(sample.Metric) < labelsString(latest.Metric) {
+			latest = sample
+		}
+	}
+
+	return latest, nil
+}
+
+// QueryScalarFromVector Prometheus query returns value of single-element vector, e.g. count(up)
+// param: query - Prometheus query string
+// result: float64 - value of the only vector element, zero or multiple elements are an error
+func (m *Client) QueryScalarFromVector(query string) (float64, error) {
+	resp, resultType, err := m.QueryRequest(query)
+	if err != nil {
+		return 0, errors.Wrapf(err, "%v: query request failed", funcInfo())
+	}
+
+	if resultType != ResultTypeVector {
+		return 0, errors.Wrapf(ErrUnexpectedResultType, "%v: got %v", funcInfo(), resultType)
+	}
+
+	samples, err := decodeVector(resp, m.maxResultSeries)
+	if err != nil {
+		return 0, errors.Wrapf(err, "%v: vector decoding failed", funcInfo())
+	}
+
+	if len(samples) != 1 {
+		return 0, errors.Errorf("%v: Vector has %v elements, expected 1", funcInfo(), len(samples))
+	}
+
+	return samples[0].Value, nil
+}
+
+// QueryTopK Prometheus query returns k samples with greatest values, limited client-side without changing the query
+// param: query - Prometheus query string, must return vector
+// param: k     - maximum number of returned samples
+// result: []Sample - samples sorted by value descending, ties are broken by labels
+func (m *Client) QueryTopK(query string, k int) ([]Sample, error) {
+	if k < 0 {
+		return nil, errors.Errorf("%v: K must not be negative, got %v", funcInfo(), k)
+	}
+
+	samples, err := m.queryVector(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: vector query failed", funcInfo())
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool {
+		if samples[i].Value != samples[j].Value {
+			return samples[i].Value > samples[j].Value
+		}
+		return labelsString(samples[i].Metric) < labelsString(samples[j].Metric)
+	})
+
+	if len(samples) > k {
+		samples = samples[:k]
+	}
+
+	return samples, nil
+}
+
+// queryVector runs instant query and decodes vector result
+func (m *Client) queryVector(query string) ([]Sample, error) {
+	resp, resultType, err := m.QueryRequest(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: query request failed", funcInfo())
+	}
+
+	if resultType != ResultTypeVector {
+		return nil, errors.Wrapf(ErrUnexpectedResultType, "%v: got %v", funcInfo(), resultType)
+	}
+
+	samples, err := decodeVector(resp, m.maxResultSeries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: vector decoding failed", funcInfo())
+	}
+
+	return samples, nil
+}
+
+// ParseVector decodes result returned by QueryRequest into samples
+// param: result     - JSON result returned by QueryRequest
+// param: resultType - result type returned by QueryRequest, must be ResultTypeVector
+// result: []Sample - samples with values parsed from their string representation
+func ParseVector(result []byte, resultType string) ([]Sample, error) {
+	if resultType != ResultTypeVector {
+		return nil, errors.Wrapf(ErrUnexpectedResultType, "%v: got %v", funcInfo(), resultType)
+	}
+
+	samples, err := decodeVector(result, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: vector decoding failed", funcInfo())
+	}
+
+	return samples, nil
+}
+
+// ParseMatrix decodes result returned by QueryRangeRequest into series
+// param: result     - JSON result returned by QueryRangeRequest
+// param: resultType - result type returned by QueryRangeRequest, must be ResultTypeMatrix
+// result: []SampleStream - series with values parsed from their string representation
+func ParseMatrix(result []byte, resultType string) ([]SampleStream, error) {
+	if resultType != ResultTypeMatrix {
+		return nil, errors.Wrapf(ErrUnexpectedResultType, "%v: got %v", funcInfo(), resultType)
+	}
+
+	streams, err := decodeMatrix(result, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: matrix decoding failed", funcInfo())
+	}
+
+	return streams, nil
+}
+
+// decodeVector decodes vector result, maxSeries greater than zero limits number of decoded series
+func decodeVector(data []byte, maxSeries int) ([]Sample, error) {
+	var samples []Sample
+
+	err := decodeSeries(data, maxSeries, func(decoder *json.Decoder) error {
+		var r struct {
+			Metric map[string]string `json:"metric"`
+			Value  SamplePair        `json:"value"`
+		}
+		if err := decoder.Decode(&r); err != nil {
+			return err
+		}
+		samples = append(samples, Sample{Metric: r.Metric, Value: r.Value.Value, Timestamp: r.Value.Timestamp})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: vector unmarshal failed", funcInfo())
+	}
+
+	return samples, nil
+}
+
+// decodeMatrix decodes matrix result, maxSeries greater than zero limits number of decoded series
+func decodeMatrix(data []byte, maxSeries int) ([]SampleStream, error) {
+	var streams []SampleStream
+
+	err := decodeSeries(data, maxSeries, func(decoder *json.Decoder) error {
+		var r struct {
+			Metric map[string]string `json:"metric"`
+			Values []SamplePair      `json:"values"`
+		}
+		if err := decoder.Decode(&r); err != nil {
+			return err
+		}
+		streams = append(streams, SampleStream{Metric: r.Metric, Values: r.Values})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: matrix unmarshal failed", funcInfo())
+	}
+
+	return streams, nil
+}
+
+// QueryRangeColumns Prometheus query range returns series as timestamp and value columns
+// param: query - Prometheus query string
+// param: start - start time of range interval
+// param: end   - end time of range interval
+// param: step  - sampling interval
+// result: []ColumnarStream - series with index-aligned Timestamps and Values
+func (m *Client) QueryRangeColumns(query string, start, end time.Time, step time.Duration) ([]ColumnarStream, error) {
+	resp, resultType, err := m.QueryRangeRequest(query, start, end, step)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: query range request failed", funcInfo())
+	}
+
+	if resultType != ResultTypeMatrix {
+		return nil, errors.Wrapf(ErrUnexpectedResultType, "%v: got %v", funcInfo(), resultType)
+	}
+
+	streams, err := decodeMatrixColumns(resp, m.maxResultSeries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: matrix decoding failed", funcInfo())
+	}
+
+	return streams, nil
+}
+
+// decodeMatrixColumns decodes matrix result straight into columns, maxSeries greater than zero limits number of decoded series
+func decodeMatrixColumns(data []byte, maxSeries int) ([]ColumnarStream, error) {
+	var streams []ColumnarStream
+
+	err := decodeSeries(data, maxSeries, func(decoder *json.Decoder) error {
+		var r struct {
+			Metric map[string]string `json:"metric"`
+			Values sampleColumns     `json:"values"`
+		}
+		if err := decoder.Decode(&r); err != nil {
+			return err
+		}
+		streams = append(streams, ColumnarStream{Metric: r.Metric, Timestamps: r.Values.timestamps, Values: r.Values.values})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: matrix unmarshal failed", funcInfo())
+	}
+
+	return streams, nil
+}
+
+// sampleColumns decodes array of sample pairs into index-aligned columns
+type sampleColumns struct {
+	timestamps []time.Time
+	values     []float64
+}
+
+// UnmarshalJSON decodes [[<unix_time>, "<value>"], ...] pair by pair without intermediate pair slice
+func (c *sampleColumns) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	if err := expectDelim(decoder, '['); err != nil {
+		return errors.Wrapf(err, "%v: values parsing failed", funcInfo())
+	}
+
+	for decoder.More() {
+		var pair SamplePair
+		if err := decoder.Decode(&pair); err != nil {
+			return errors.Wrapf(err, "%v: sample pair unmarshal failed", funcInfo())
+		}
+		c.timestamps = append(c.timestamps, pair.Timestamp)
+		c.values = append(c.values, pair.Value)
+	}
+
+	return expectDelim(decoder, ']')
+}
+
+// decodeSeries decodes result array element by element, aborting as soon as maxSeries is exceeded
+func decodeSeries(data []byte, maxSeries int, decodeElement func(*json.Decoder) error) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	if err := expectDelim(decoder, '['); err != nil {
+		return errors.Wrapf(err, "%v: result parsing failed", funcInfo())
+	}
+
+	for count := 0; decoder.More(); count++ {
+		if maxSeries > 0 && count >= maxSeries {
+			return errors.Errorf("%v: Result exceeds maximum of %v series", funcInfo(), maxSeries)
+		}
+
+		if err := decodeElement(decoder); err != nil {
+			return errors.Wrapf(err, "%v: series unmarshal failed", funcInfo())
+		}
+	}
+
+	return expectDelim(decoder, ']')
+}
+
+// latestSamples returns last sample of every non-empty stream
+func latestSamples(streams []SampleStream) []Sample {
+	samples := make([]Sample, 0, len(streams))
+	for _, stream := range streams {
+		if len(stream.Values) == 0 {
+			continue
+		}
+		last := stream.Values[len(stream.Values)-1]
+		samples = append(samples, Sample{Metric: stream.Metric, Value: last.Value, Timestamp: last.Timestamp})
+	}
+
+	return samples
+}
+
+// labelsString returns labels formatted as sorted {name="value",...} selector
+func labelsString(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+`="`+EscapeLabelValue(labels[name])+`"`)
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// unixToTime converts Prometheus float unix timestamp with millisecond precision to time.Time
+func unixToTime(ts float64) time.Time {
+	sec, frac := math.Modf(ts)
+	return time.Unix(int64(sec), int64(math.Round(frac*1000))*int64(time.Millisecond))
+}
@@ -0,0 +1,555 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var (
+	latestVectorResponse = []byte(`{"data":{"resultType":"vector","result":[` +
+		`{"metric":{"instance":"a"},"value":[1500000000.5,"1"]},` +
+		`{"metric":{"instance":"b"},"value":[1500000002,"2"]},` +
+		`{"metric":{"instance":"c"},"value":[1500000001,"3"]}]}}`)
+	latestMatrixResponse = []byte(`{"data":{"resultType":"matrix","result":[` +
+		`{"metric":{"instance":"a"},"values":[[1500000000,"1"],[1500000010,"4"]]},` +
+		`{"metric":{"instance":"b"},"values":[[1500000005,"2"]]}]}}`)
+	latestTieResponse = []byte(`{"data":{"resultType":"vector","result":[` +
+		`{"metric":{"instance":"b"},"value":[1500000000,"2"]},` +
+		`{"metric":{"instance":"a"},"value":[1500000000,"1"]}]}}`)
+	scalarResponse = []byte(`{"data":{"resultType":"scalar","result":[1500000000,"1"]}}`)
+)
+
+func responseHandler(response []byte) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, string(response))
+	}
+}
+
+func TestClient_QueryLatest(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	type args struct {
+		query   string
+		handler func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name    string
+		m       *Client
+		args    args
+		want    Sample
+		wantErr bool
+	}{
+		{
+			name: "Test QueryLatest vector",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{query: "QUERY", handler: responseHandler(latestVectorResponse)},
+			want: Sample{Metric: map[string]string{"instance": "b"}, Value: 2, Timestamp: time.Unix(1500000002, 0)},
+		},
+		{
+			name: "Test QueryLatest matrix",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{query: "QUERY", handler: responseHandler(latestMatrixResponse)},
+			want: Sample{Metric: map[string]string{"instance": "a"}, Value: 4, Timestamp: time.Unix(1500000010, 0)},
+		},
+		{
+			name: "Test QueryLatest tie broken by labels",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{query: "QUERY", handler: responseHandler(latestTieResponse)},
+			want: Sample{Metric: map[string]string{"instance": "a"}, Value: 1, Timestamp: time.Unix(1500000000, 0)},
+		},
+		{
+			name:    "Test QueryLatest result type fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{query: "QUERY", handler: responseHandler(scalarResponse)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query", "9090", tt.args.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.QueryLatest(tt.args.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryLatest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got.Metric, tt.want.Metric) || got.Value != tt.want.Value || !got.Timestamp.Equal(tt.want.Timestamp) {
+				t.Errorf("Client.QueryLatest() got = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_QueryScalarFromVector(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name      string
+		m         *Client
+		handler   func(w http.ResponseWriter, r *http.Request)
+		want      float64
+		wantCause error
+		wantErr   bool
+	}{
+		{
+			name:    "Test QueryScalarFromVector one element",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: responseHandler([]byte(`{"data":{"resultType":"vector","result":[{"metric":{},"value":[1500000000,"42"]}]}}`)),
+			want:    42,
+		},
+		{
+			name:    "Test QueryScalarFromVector multiple elements",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: responseHandler(latestVectorResponse),
+			wantErr: true,
+		},
+		{
+			name:    "Test QueryScalarFromVector empty",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: responseHandler(resultsFailResponse),
+			wantErr: true,
+		},
+		{
+			name:      "Test QueryScalarFromVector scalar result type",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler:   responseHandler(scalarResponse),
+			wantCause: ErrUnexpectedResultType,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query", "9090", tt.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.QueryScalarFromVector("count(up)")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryScalarFromVector() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantCause != nil && errors.Cause(err) != tt.wantCause {
+				t.Errorf("Client.QueryScalarFromVector() error = %v, want %v", err, tt.wantCause)
+			}
+			if got != tt.want {
+				t.Errorf("Client.QueryScalarFromVector() = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+var topKResponse = []byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+	`{"metric":{"instance":"a"},"value":[1500000000,"1"]},` +
+	`{"metric":{"instance":"b"},"value":[1500000000,"5"]},` +
+	`{"metric":{"instance":"c"},"value":[1500000000,"3"]},` +
+	`{"metric":{"instance":"d"},"value":[1500000000,"5"]}]}}`)
+
+func TestClient_QueryTopK(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	ts := time.Unix(1500000000, 0)
+
+	tests := []struct {
+		name    string
+		m       *Client
+		handler func(w http.ResponseWriter, r *http.Request)
+		k       int
+		want    []Sample
+		wantErr bool
+	}{
+		{
+			name:    "Test QueryTopK unicorn path",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: responseHandler(topKResponse),
+			k:       2,
+			want: []Sample{
+				{Metric: map[string]string{"instance": "b"}, Value: 5, Timestamp: ts},
+				{Metric: map[string]string{"instance": "d"}, Value: 5, Timestamp: ts},
+			},
+		},
+		{
+			name:    "Test QueryTopK k exceeds result",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: responseHandler(topKResponse),
+			k:       10,
+			want: []Sample{
+				{Metric: map[string]string{"instance": "b"}, Value: 5, Timestamp: ts},
+				{Metric: map[string]string{"instance": "d"}, Value: 5, Timestamp: ts},
+				{Metric: map[string]string{"instance": "c"}, Value: 3, Timestamp: ts},
+				{Metric: map[string]string{"instance": "a"}, Value: 1, Timestamp: ts},
+			},
+		},
+		{
+			name:    "Test QueryTopK negative k",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: responseHandler(topKResponse),
+			k:       -1,
+			wantErr: true,
+		},
+		{
+			name:    "Test QueryTopK scalar result type",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: responseHandler(scalarResponse),
+			k:       2,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query", "9090", tt.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.QueryTopK("QUERY", tt.k)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryTopK() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.QueryTopK() = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestParseVector(t *testing.T) {
+	type args struct {
+		result     []byte
+		resultType string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []Sample
+		wantErr bool
+	}{
+		{
+			name: "Test ParseVector unicorn path",
+			args: args{
+				result:     []byte(`[{"metric":{"instance":"a"},"value":[1500000000.25,"1.5"]},{"metric":{},"value":[1500000000,"NaN"]}]`),
+				resultType: ResultTypeVector,
+			},
+			want: []Sample{
+				{Metric: map[string]string{"instance": "a"}, Value: 1.5, Timestamp: time.Unix(1500000000, 250*int64(time.Millisecond))},
+				{Metric: map[string]string{}, Value: math.NaN(), Timestamp: time.Unix(1500000000, 0)},
+			},
+		},
+		{
+			name:    "Test ParseVector wrong result type",
+			args:    args{result: []byte(`[]`), resultType: ResultTypeMatrix},
+			wantErr: true,
+		},
+		{
+			name:    "Test ParseVector invalid value",
+			args:    args{result: []byte(`[{"metric":{},"value":[1500000000,"abc"]}]`), resultType: ResultTypeVector},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVector(tt.args.result, tt.args.resultType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseVector() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseVector() got = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !reflect.DeepEqual(got[i].Metric, tt.want[i].Metric) || !got[i].Timestamp.Equal(tt.want[i].Timestamp) ||
+					!(got[i].Value == tt.want[i].Value || math.IsNaN(got[i].Value) && math.IsNaN(tt.want[i].Value)) {
+					t.Errorf("ParseVector() got[%v] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseMatrix(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Unix(1500000000, 0)
+
+	rangeResponse := `{"status":"success","data":{"resultType":"matrix","result":[` +
+		`{"metric":{"__name__":"up","instance":"localhost:9090","job":"prometheus"},` +
+		`"values":[[1500000000,"1"],[1500000015,"1"],[1500000030,"0"]]},` +
+		`{"metric":{"__name__":"up","instance":"localhost:9100","job":"node"},` +
+		`"values":[[1500000000,"0"],[1500000015.5,"1"]]},` +
+		`{"metric":{"__name__":"up","instance":"localhost:9200","job":"stale"},"values":[]}]}}`
+
+	tests := []struct {
+		name    string
+		m       *Client
+		want    []SampleStream
+		wantErr bool
+	}{
+		{
+			name: "Test ParseMatrix round trip",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			want: []SampleStream{
+				{
+					Metric: map[string]string{"__name__": "up", "instance": "localhost:9090", "job": "prometheus"},
+					Values: []SamplePair{{Timestamp: start, Value: 1}, {Timestamp: start.Add(15 * time.Second), Value: 1}, {Timestamp: start.Add(30 * time.Second), Value: 0}},
+				},
+				{
+					Metric: map[string]string{"__name__": "up", "instance": "localhost:9100", "job": "node"},
+					Values: []SamplePair{{Timestamp: start, Value: 0}, {Timestamp: start.Add(15500 * time.Millisecond), Value: 1}},
+				},
+				{
+					Metric: map[string]string{"__name__": "up", "instance": "localhost:9200", "job": "stale"},
+					Values: []SamplePair{},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query_range", "9090", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, rangeResponse)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			resp, resultType, err := tt.m.QueryRangeRequest("up", start, start.Add(30*time.Second), 15*time.Second)
+			if err != nil {
+				t.Fatalf("Client.QueryRangeRequest() error = %v", err)
+			}
+
+			got, err := ParseMatrix(resp, resultType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseMatrix() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMatrix() got = %v, want %v", got, tt.want)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+
+	if _, err := ParseMatrix([]byte(`[]`), ResultTypeVector); err == nil {
+		t.Errorf("ParseMatrix() error = nil for result type %v", ResultTypeVector)
+	}
+}
+
+func Test_decodeVector(t *testing.T) {
+	threeSamples := []byte(`[{"metric":{"instance":"a"},"value":[1500000000,"1"]},` +
+		`{"metric":{"instance":"b"},"value":[1500000000,"2"]},` +
+		`{"metric":{"instance":"c"},"value":[1500000000,"3"]}]`)
+
+	type args struct {
+		data      []byte
+		maxSeries int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "Test decodeVector unlimited",
+			args: args{data: threeSamples},
+			want: 3,
+		},
+		{
+			name: "Test decodeVector within limit",
+			args: args{data: threeSamples, maxSeries: 3},
+			want: 3,
+		},
+		{
+			name:    "Test decodeVector limit exceeded",
+			args:    args{data: threeSamples, maxSeries: 2},
+			wantErr: true,
+		},
+		{
+			name:    "Test decodeVector not array",
+			args:    args{data: []byte(`{}`)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeVector(tt.args.data, tt.args.maxSeries)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeVector() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.want {
+				t.Errorf("decodeVector() got = %v, want %v samples", got, tt.want)
+			}
+		})
+	}
+}
+
+var columnsMatrixResponse = []byte(`{"status":"success","data":{"resultType":"matrix","result":[` +
+	`{"metric":{"instance":"a"},"values":[[1500000000,"1"],[1500000015.5,"2"],[1500000030,"3"]]},` +
+	`{"metric":{"instance":"b"},"values":[]}]}}`)
+
+func TestClient_QueryRangeColumns(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Unix(1500000000, 0)
+
+	tests := []struct {
+		name    string
+		m       *Client
+		handler func(w http.ResponseWriter, r *http.Request)
+		want    []ColumnarStream
+		wantErr bool
+	}{
+		{
+			name:    "Test QueryRangeColumns unicorn path",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: responseHandler(columnsMatrixResponse),
+			want: []ColumnarStream{
+				{
+					Metric:     map[string]string{"instance": "a"},
+					Timestamps: []time.Time{start, start.Add(15500 * time.Millisecond), start.Add(30 * time.Second)},
+					Values:     []float64{1, 2, 3},
+				},
+				{Metric: map[string]string{"instance": "b"}},
+			},
+		},
+		{
+			name:    "Test QueryRangeColumns vector result type",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: responseHandler(latestVectorResponse),
+			wantErr: true,
+		},
+		{
+			name:    "Test QueryRangeColumns sample fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: responseHandler([]byte(`{"data":{"resultType":"matrix","result":[{"metric":{},"values":[[1500000000]]}]}}`)),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query_range", "9090", tt.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.QueryRangeColumns("QUERY", start, start.Add(time.Minute), 15*time.Second)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRangeColumns() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.QueryRangeColumns() = %v, want %v", got, tt.want)
+			}
+			for _, stream := range got {
+				if len(stream.Timestamps) != len(stream.Values) {
+					t.Errorf("Client.QueryRangeColumns() %v timestamps = %v, values = %v", stream.Metric, len(stream.Timestamps), len(stream.Values))
+				}
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestWithMaxResultSeries(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name    string
+		m       *Client
+		wantErr bool
+	}{
+		{
+			name: "Test WithMaxResultSeries within limit",
+			m:    NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithMaxResultSeries(2)),
+		},
+		{
+			name:    "Test WithMaxResultSeries limit exceeded",
+			m:       NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithMaxResultSeries(1)),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query_range", "9090", responseHandler(twoSeriesMatrixResponse))
+
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.m.QueryRangeFrames("up", time.Unix(1500000000, 0), time.Unix(1500000030, 0), time.Second*15)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRangeFrames() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestSamplePair_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    SamplePair
+		wantErr bool
+	}{
+		{
+			name: "Test SamplePair unicorn path",
+			data: []byte(`[1500000000.123,"1.5"]`),
+			want: SamplePair{Timestamp: time.Unix(1500000000, 123*int64(time.Millisecond)), Value: 1.5},
+		},
+		{
+			name:    "Test SamplePair length fail",
+			data:    []byte(`[1500000000]`),
+			wantErr: true,
+		},
+		{
+			name:    "Test SamplePair timestamp fail",
+			data:    []byte(`["1500000000","1"]`),
+			wantErr: true,
+		},
+		{
+			name:    "Test SamplePair value fail",
+			data:    []byte(`[1500000000,"x"]`),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got SamplePair
+			err := got.UnmarshalJSON(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SamplePair.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.Value != tt.want.Value || !got.Timestamp.Equal(tt.want.Timestamp) {
+				t.Errorf("SamplePair.UnmarshalJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_labelsString(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{
+		{
+			name:   "Test labelsString sorted",
+			labels: map[string]string{"job": "api", "instance": "a"},
+			want:   `{instance="a",job="api"}`,
+		},
+		{
+			name:   "Test labelsString empty",
+			labels: map[string]string{},
+			want:   `{}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := labelsString(tt.labels); got != tt.want {
+				t.Errorf("labelsString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
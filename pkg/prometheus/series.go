@@ -0,0 +1,193 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SeriesStream streams series matching label matchers without loading whole response into memory,
+// matchers too long to fit in URL are sent in form-encoded POST body
+// param: matchers - series selectors, at least one is required
+// param: start    - start time of series interval, zero time is omitted
+// param: end      - end time of series interval, zero time is omitted
+// param: fn       - called with label set of each series, returning error stops iteration
+func (m *Client) SeriesStream(matchers []string, start, end time.Time, fn func(map[string]string) error) error {
+	if len(matchers) == 0 {
+		return errors.Errorf("%v: At least one matcher is required", funcInfo())
+	}
+
+	resp, err := m.getOrPost(context.Background(), "series", seriesParams(matchers, start, end))
+	if err != nil {
+		return errors.Wrapf(err, "%v: series request failed", funcInfo())
+	}
+	defer resp.Body.Close()
+
+	if err := seriesStatusError(resp); err != nil {
+		return errors.Wrapf(err, "%v: series request failed", funcInfo())
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+
+	if err := expectDelim(decoder, '{'); err != nil {
+		return errors.Wrapf(err, "%v: response parsing failed", funcInfo())
+	}
+
+	var status APIError
+	var statusField string
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return errors.Wrapf(err, "%v: response key parsing failed", funcInfo())
+		}
+
+		var field *string
+		switch token {
+		case "data":
+		case "status":
+			field = &statusField
+		case "errorType":
+			field = &status.Type
+		case "error":
+			field = &status.Message
+		default:
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return errors.Wrapf(err, "%v: response value parsing failed", funcInfo())
+			}
+			continue
+		}
+
+		if field != nil {
+			if err := decoder.Decode(field); err != nil {
+				return errors.Wrapf(err, "%v: response %v parsing failed", funcInfo(), token)
+			}
+			continue
+		}
+
+		if statusField != "" && statusField != statusSuccessResponse {
+			return errors.Errorf("%v: Unexpected response status %q", funcInfo(), statusField)
+		}
+
+		if err := expectDelim(decoder, '['); err != nil {
+			return errors.Wrapf(err, "%v: data parsing failed", funcInfo())
+		}
+
+		for decoder.More() {
+			var series map[string]string
+			if err := decoder.Decode(&series); err != nil {
+				return errors.Wrapf(err, "%v: series unmarshal failed", funcInfo())
+			}
+
+			if err := fn(series); err != nil {
+				return errors.Wrapf(err, "%v: series callback failed", funcInfo())
+			}
+		}
+
+		return nil
+	}
+
+	if statusField == statusErrorResponse {
+		return errors.Wrapf(&status, "%v: series query failed", funcInfo())
+	}
+
+	return errors.Errorf("%v: Data parsing failed", funcInfo())
+}
+
+// seriesStatusError returns ErrNotSupported for 404 and error of responseStatusError for other non-2xx
+// response, body of which is read only in that case so successful response can still be streamed
+func seriesStatusError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.Wrapf(ErrNotSupported, "%v: series", funcInfo())
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, err := readBody(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "%v: reading response body failed", funcInfo())
+	}
+	defer releaseBody(body)
+
+	return responseStatusError(resp, body.Bytes())
+}
+
+// Series returns label sets of series matching label matchers
+// param: matches - series selectors, at least one is required, each is checked by ValidateMatcher
+// param: start   - start time of series interval, zero time is omitted
+// param: end     - end time of series interval, zero time is omitted
+// result: []map[string]string - label set of each matching series
+func (m *Client) Series(matches []string, start, end time.Time) ([]map[string]string, error) {
+	if len(matches) == 0 {
+		return nil, errors.Errorf("%v: At least one matcher is required", funcInfo())
+	}
+	if err := validateMatchers(matches); err != nil {
+		return nil, errors.Wrapf(err, "%v: invalid matcher", funcInfo())
+	}
+
+	series := []map[string]string{}
+
+	err := m.SeriesStream(matches, start, end, func(labels map[string]string) error {
+		series = append(series, labels)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: series request failed", funcInfo())
+	}
+
+	return series, nil
+}
+
+// errSeriesFound stops series iteration as soon as first series is seen
+var errSeriesFound = errors.New("series found")
+
+// AssertSeriesExist checks that at least one series matches label matchers, e.g. whether metric is scraped
+// param: matchers - series selectors, at least one is required
+// result: error - nil when one or more series exist, error otherwise
+func (m *Client) AssertSeriesExist(matchers []string) error {
+	err := m.SeriesStream(matchers, time.Time{}, time.Time{}, func(map[string]string) error {
+		return errSeriesFound
+	})
+	if errors.Cause(err) == errSeriesFound {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "%v: series request failed", funcInfo())
+	}
+
+	return errors.Errorf("%v: No series match %v", funcInfo(), matchers)
+}
+
+func seriesParams(matchers []string, start, end time.Time) url.Values {
+	params := url.Values{}
+	for _, matcher := range matchers {
+		params.Add("match[]", matcher)
+	}
+	if !start.IsZero() {
+		params.Set("start", formatTime(start))
+	}
+	if !end.IsZero() {
+		params.Set("end", formatTime(end))
+	}
+
+	return params
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return errors.Wrapf(err, "%v: token parsing failed", funcInfo())
+	}
+
+	if token != delim {
+		return errors.Errorf("%v: Expected %v, got %v", funcInfo(), delim, token)
+	}
+
+	return nil
+}
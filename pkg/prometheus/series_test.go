@@ -0,0 +1,406 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var errStopSeries = errors.New("stop")
+
+func manySeriesHandler(count int) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		series := make([]string, 0, count)
+		for i := 0; i < count; i++ {
+			series = append(series, fmt.Sprintf(`{"__name__":"up","instance":"host-%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"status":"success","data":[%v]}`, strings.Join(series, ","))
+	}
+}
+
+func TestClient_SeriesStream(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	type args struct {
+		matchers []string
+		stopAt   int
+		handler  func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name      string
+		m         *Client
+		args      args
+		wantCalls int
+		wantCause error
+		wantErr   bool
+	}{
+		{
+			name:      "Test SeriesStream unicorn path",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(zap.NewNop()), timeout: time.Second * 30},
+			args:      args{matchers: []string{"up"}, handler: manySeriesHandler(1000)},
+			wantCalls: 1000,
+		},
+		{
+			name:      "Test SeriesStream early exit",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(zap.NewNop()), timeout: time.Second * 30},
+			args:      args{matchers: []string{"up"}, stopAt: 10, handler: manySeriesHandler(1000)},
+			wantCalls: 10,
+			wantCause: errStopSeries,
+			wantErr:   true,
+		},
+		{
+			name:    "Test SeriesStream matchers fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{handler: manySeriesHandler(1)},
+			wantErr: true,
+		},
+		{
+			name:    "Test SeriesStream data fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{matchers: []string{"up"}, handler: dataFailhandler},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/series", "9090", tt.args.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := tt.m.SeriesStream(tt.args.matchers, time.Time{}, time.Time{}, func(series map[string]string) error {
+				if want := map[string]string{"__name__": "up", "instance": fmt.Sprintf("host-%d", calls)}; !reflect.DeepEqual(series, want) {
+					t.Errorf("Client.SeriesStream() series = %v, want %v", series, want)
+				}
+				calls++
+				if calls == tt.args.stopAt {
+					return errStopSeries
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.SeriesStream() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantCause != nil && errors.Cause(err) != tt.wantCause {
+				t.Errorf("Client.SeriesStream() error = %v, want %v", err, tt.wantCause)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Client.SeriesStream() calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_SeriesStream_errors(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name       string
+		status     int
+		response   string
+		wantAPI    *APIError
+		wantStatus int
+		wantErr    error
+	}{
+		{
+			name:     "Test SeriesStream bad request",
+			status:   http.StatusBadRequest,
+			response: `{"status":"error","errorType":"bad_data","error":"invalid matcher"}`,
+			wantAPI:  &APIError{StatusCode: http.StatusBadRequest, Type: "bad_data", Message: "invalid matcher"},
+		},
+		{
+			name:       "Test SeriesStream server failure",
+			status:     http.StatusServiceUnavailable,
+			response:   "upstream unavailable",
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:    "Test SeriesStream not supported",
+			status:  http.StatusNotFound,
+			wantErr: ErrNotSupported,
+		},
+		{
+			name:     "Test SeriesStream error status with 200",
+			status:   http.StatusOK,
+			response: `{"status":"error","errorType":"execution","error":"too many series"}`,
+			wantAPI:  &APIError{Type: "execution", Message: "too many series"},
+		},
+		{
+			name:     "Test SeriesStream unexpected status",
+			status:   http.StatusOK,
+			response: `{"status":"pending","data":[{"__name__":"up"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/series", "9090", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			fmt.Fprint(w, tt.response)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+
+			calls := 0
+			err := m.SeriesStream([]string{"up"}, time.Time{}, time.Time{}, func(map[string]string) error {
+				calls++
+				return nil
+			})
+			if err == nil || calls != 0 {
+				t.Fatalf("Client.SeriesStream() error = %v, calls = %v, want error and no calls", err, calls)
+			}
+
+			var apiErr *APIError
+			if tt.wantAPI != nil && (!errors.As(err, &apiErr) || *apiErr != *tt.wantAPI) {
+				t.Errorf("Client.SeriesStream() error = %v, want %v", err, tt.wantAPI)
+			}
+			var statusErr *StatusError
+			if tt.wantStatus != 0 && (!errors.As(err, &statusErr) || statusErr.StatusCode != tt.wantStatus) {
+				t.Errorf("Client.SeriesStream() error = %v, want status %v", err, tt.wantStatus)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Client.SeriesStream() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_Series(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	type args struct {
+		matches []string
+		handler func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name        string
+		m           *Client
+		args        args
+		want        []map[string]string
+		wantMatches []string
+		wantInvalid string
+		wantErr     bool
+	}{
+		{
+			name:        "Test Series unicorn path",
+			m:           &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:        args{matches: []string{"up", `process_start_time_seconds{job="prometheus"}`}, handler: manySeriesHandler(2)},
+			want:        []map[string]string{{"__name__": "up", "instance": "host-0"}, {"__name__": "up", "instance": "host-1"}},
+			wantMatches: []string{"up", `process_start_time_seconds{job="prometheus"}`},
+		},
+		{
+			name:        "Test Series no series",
+			m:           &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:        args{matches: []string{"up"}, handler: manySeriesHandler(0)},
+			want:        []map[string]string{},
+			wantMatches: []string{"up"},
+		},
+		{
+			name:    "Test Series matchers fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{handler: manySeriesHandler(2)},
+			wantErr: true,
+		},
+		{
+			name:        "Test Series invalid matcher",
+			m:           &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:        args{matches: []string{"up", `{job=}`}, handler: manySeriesHandler(2)},
+			wantInvalid: `{job=}`,
+			wantErr:     true,
+		},
+		{
+			name:        "Test Series data fail",
+			m:           &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:        args{matches: []string{"up"}, handler: dataFailhandler},
+			wantMatches: []string{"up"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		var gotMatches []string
+		httpServer := startHTTPServer("/api/v1/series", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotMatches = r.URL.Query()["match[]"]
+			tt.args.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Series(tt.args.matches, time.Time{}, time.Time{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Series() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && tt.wantInvalid != "" && !strings.Contains(err.Error(), strconv.Quote(tt.wantInvalid)) {
+				t.Errorf("Client.Series() error = %v, want invalid matcher %v named", err, tt.wantInvalid)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.Series() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotMatches, tt.wantMatches) {
+				t.Errorf("Client.Series() match[] params = %v, want %v", gotMatches, tt.wantMatches)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_Series_manyMatchers(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name       string
+		m          *Client
+		matchers   int
+		wantMethod string
+	}{
+		{
+			name:       "Test Series few matchers",
+			m:          &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			matchers:   3,
+			wantMethod: http.MethodGet,
+		},
+		{
+			name:       "Test Series matchers over default URL length",
+			m:          &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			matchers:   500,
+			wantMethod: http.MethodPost,
+		},
+		{
+			name:       "Test Series matchers over detected URL length",
+			m:          &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30, maxURLLength: 512},
+			matchers:   20,
+			wantMethod: http.MethodPost,
+		},
+	}
+	for _, tt := range tests {
+		http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+
+		var gotMethod string
+		httpServer := startHTTPServer("/api/v1/series", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotMethod = r.Method
+			if err := r.ParseForm(); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			series := []string{}
+			for _, matcher := range r.Form["match[]"] {
+				instance := strings.TrimSuffix(strings.TrimPrefix(matcher, `up{instance="`), `"}`)
+				series = append(series, `{"__name__":"up","instance":"`+instance+`"}`)
+			}
+			fmt.Fprint(w, `{"status":"success","data":[`+strings.Join(series, ",")+`]}`)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			matchers := make([]string, tt.matchers)
+			for i := range matchers {
+				matchers[i] = `up{instance="host-` + strconv.Itoa(i) + `.example.com:9100"}`
+			}
+
+			got, err := tt.m.Series(matchers, time.Time{}, time.Time{})
+			if err != nil {
+				t.Fatalf("Client.Series() error = %v", err)
+			}
+			if gotMethod != tt.wantMethod {
+				t.Errorf("Client.Series() method = %v, want %v", gotMethod, tt.wantMethod)
+			}
+			if len(got) != tt.matchers {
+				t.Fatalf("Client.Series() got %v series, want %v", len(got), tt.matchers)
+			}
+			for i, series := range got {
+				if want := "host-" + strconv.Itoa(i) + ".example.com:9100"; series["instance"] != want {
+					t.Errorf("Client.Series() series %v instance = %v, want %v", i, series["instance"], want)
+				}
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_AssertSeriesExist(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	type args struct {
+		matchers []string
+		handler  func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name    string
+		m       *Client
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Test AssertSeriesExist unicorn path",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args: args{matchers: []string{"up"}, handler: manySeriesHandler(3)},
+		},
+		{
+			name:    "Test AssertSeriesExist no series",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{matchers: []string{"up"}, handler: manySeriesHandler(0)},
+			wantErr: true,
+		},
+		{
+			name:    "Test AssertSeriesExist data fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			args:    args{matchers: []string{"up"}, handler: dataFailhandler},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/series", "9090", tt.args.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.AssertSeriesExist(tt.args.matchers); (err != nil) != tt.wantErr {
+				t.Errorf("Client.AssertSeriesExist() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func Test_seriesParams(t *testing.T) {
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	type args struct {
+		matchers []string
+		start    time.Time
+		end      time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Test seriesParams matchers only",
+			args: args{matchers: []string{"up", `process_start_time_seconds{job="prometheus"}`}},
+			want: "match%5B%5D=up&match%5B%5D=process_start_time_seconds%7Bjob%3D%22prometheus%22%7D",
+		},
+		{
+			name: "Test seriesParams time bounds",
+			args: args{matchers: []string{"up"}, start: start, end: end},
+			want: "end=2019-01-01T01%3A00%3A00Z&match%5B%5D=up&start=2019-01-01T00%3A00%3A00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := seriesParams(tt.args.matchers, tt.args.start, tt.args.end).Encode(); got != tt.want {
+				t.Errorf("seriesParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ServerNow returns current time of Prometheus server evaluated by time() query,
+// e.g. to anchor relative time ranges to server clock instead of client clock
+// result: time.Time - server time with millisecond precision
+func (m *Client) ServerNow() (time.Time, error) {
+	resp, resultType, err := m.QueryRequest("time()")
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "%v: query request failed", funcInfo())
+	}
+
+	if resultType != ResultTypeScalar {
+		return time.Time{}, errors.Wrapf(ErrUnexpectedResultType, "%v: got %v", funcInfo(), resultType)
+	}
+
+	var pair SamplePair
+	if err := json.Unmarshal(resp, &pair); err != nil {
+		return time.Time{}, errors.Wrapf(err, "%v: scalar decoding failed", funcInfo())
+	}
+
+	if math.IsNaN(pair.Value) || math.IsInf(pair.Value, 0) {
+		return time.Time{}, errors.Errorf("%v: Invalid server time %v", funcInfo(), pair.Value)
+	}
+
+	return time.Unix(0, int64(math.Round(pair.Value*1e3))*int64(time.Millisecond)), nil
+}
@@ -0,0 +1,68 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestClient_ServerNow(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name     string
+		response string
+		want     time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "Test ServerNow unicorn path",
+			response: `{"status":"success","data":{"resultType":"scalar","result":[1554000000.123,"1554000000.123"]}}`,
+			want:     time.Date(2019, 3, 31, 2, 40, 0, 123000000, time.UTC),
+		},
+		{
+			name:     "Test ServerNow whole seconds",
+			response: `{"status":"success","data":{"resultType":"scalar","result":[1554000000,"1554000000"]}}`,
+			want:     time.Date(2019, 3, 31, 2, 40, 0, 0, time.UTC),
+		},
+		{
+			name:     "Test ServerNow vector result",
+			response: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1554000000,"1"]}]}}`,
+			wantErr:  true,
+		},
+		{
+			name:     "Test ServerNow NaN",
+			response: `{"status":"success","data":{"resultType":"scalar","result":[1554000000,"NaN"]}}`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		var gotQuery string
+		httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotQuery = r.URL.Query().Get("query")
+			fmt.Fprint(w, tt.response)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+
+			got, err := m.ServerNow()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.ServerNow() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Client.ServerNow() = %v, want %v", got.UTC(), tt.want)
+			}
+			if gotQuery != "time()" {
+				t.Errorf("Client.ServerNow() query = %q, want %q", gotQuery, "time()")
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
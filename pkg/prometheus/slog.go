@@ -0,0 +1,41 @@
+//go:build go1.21
+// +build go1.21
+
+package prometheus
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithSlog sets standard library slog.Logger as Client logger
+func WithSlog(logger *slog.Logger) Option {
+	return func(args *Client) {
+		args.logger = NewSlogLogger(logger)
+	}
+}
+
+// NewSlogLogger adapts slog.Logger to Logger interface, keys and values become slog attributes
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return &slogLogger{logger: logger}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l *slogLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.logger.Debug(msg, keysAndValues...)
+}
+
+func (l *slogLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.logger.Warn(msg, keysAndValues...)
+}
+
+func (l *slogLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.logger.Error(msg, keysAndValues...)
+}
+
+func (l *slogLogger) DebugEnabled() bool {
+	return l.logger.Enabled(context.Background(), slog.LevelDebug)
+}
@@ -0,0 +1,112 @@
+//go:build go1.21
+// +build go1.21
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type recordingHandler struct {
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, record slog.Record) error {
+	h.records = append(h.records, record)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func TestWithSlog(t *testing.T) {
+	handler := &recordingHandler{}
+	m := NewClient("http", "127.0.0.1", "9090", WithSlog(slog.New(handler)), WithTimeout(time.Second*30))
+
+	httpServer := startHTTPServer("/api/v1/query", "9090", unicornHandler)
+	defer func() { httpServer.Shutdown(context.Background()) }()
+
+	if _, _, err := m.QueryRequest("QUERY"); err != nil {
+		t.Fatalf("Client.QueryRequest() error = %v", err)
+	}
+	// records are inspected only after the second response reused the pooled body buffer
+	httpServer.Shutdown(context.Background())
+	httpServer = startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"resultType":"vector","result":[{"value":[2.2,"2"]}]}}`)
+	})
+	if _, _, err := m.QueryRequest("QUERY"); err != nil {
+		t.Fatalf("Client.QueryRequest() error = %v", err)
+	}
+	handler.records = handler.records[:2]
+
+	tests := []struct {
+		name  string
+		msg   string
+		key   string
+		want  string
+		level slog.Level
+	}{
+		{
+			name:  "Test WithSlog request",
+			msg:   "Prometheus request",
+			key:   "query",
+			want:  "http://127.0.0.1:9090/api/v1/query?query=QUERY",
+			level: slog.LevelDebug,
+		},
+		{
+			name:  "Test WithSlog response",
+			msg:   "Prometheus response",
+			key:   "result",
+			want:  string(unicornResponse),
+			level: slog.LevelDebug,
+		},
+	}
+	if len(handler.records) != len(tests) {
+		t.Fatalf("slog records = %v, want %v", len(handler.records), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := handler.records[i]
+			if record.Message != tt.msg || record.Level != tt.level {
+				t.Errorf("slog record = %v %v, want %v %v", record.Level, record.Message, tt.level, tt.msg)
+			}
+			var got string
+			record.Attrs(func(attr slog.Attr) bool {
+				if attr.Key == tt.key {
+					got = attr.Value.Resolve().String()
+				}
+				return true
+			})
+			if got != tt.want {
+				t.Errorf("slog attr %v = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSlogLogger_levels(t *testing.T) {
+	handler := &recordingHandler{}
+	logger := NewSlogLogger(slog.New(handler))
+
+	logger.Debug("debug message", "step", time.Minute)
+	logger.Warn("warn message")
+	logger.Error("error message")
+
+	want := []slog.Level{slog.LevelDebug, slog.LevelWarn, slog.LevelError}
+	if len(handler.records) != len(want) {
+		t.Fatalf("slog records = %v, want %v", len(handler.records), len(want))
+	}
+	for i, level := range want {
+		if handler.records[i].Level != level {
+			t.Errorf("slog record %v level = %v, want %v", i, handler.records[i].Level, level)
+		}
+	}
+}
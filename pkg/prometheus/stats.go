@@ -0,0 +1,45 @@
+package prometheus
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// QueryStats sample statistics of query evaluation, returned when WithQueryStats is enabled
+type QueryStats struct {
+	// TotalQueryableSamples total number of samples loaded while evaluating the query
+	TotalQueryableSamples int64 `json:"totalQueryableSamples"`
+	// PeakSamples maximum number of samples held in memory at once during evaluation
+	PeakSamples int64 `json:"peakSamples"`
+}
+
+// WithQueryStats requests query statistics (stats=all) from server,
+// statistics are exposed as QueryResult.Stats, e.g. by QueryAt
+func WithQueryStats() Option {
+	return func(args *Client) {
+		args.queryStats = true
+	}
+}
+
+// parseStats returns sample statistics from optional 'data.stats' field of response
+func parseStats(data []byte) (*QueryStats, error) {
+	var response struct {
+		Data struct {
+			Stats *struct {
+				Samples *QueryStats `json:"samples"`
+			} `json:"stats"`
+		} `json:"data"`
+	}
+
+	err := json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: stats unmarshal failed", funcInfo())
+	}
+
+	if response.Data.Stats == nil {
+		return nil, nil
+	}
+
+	return response.Data.Stats.Samples, nil
+}
@@ -0,0 +1,76 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const statsResponse = `{"status":"success","data":{"resultType":"vector","result":[{"value":[1.1,"1"]}],` +
+	`"stats":{"timings":{"evalTotalTime":0.000447},"samples":{"totalQueryableSamples":1500,"peakSamples":42}}}}`
+
+func TestClient_QueryAt_stats(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name      string
+		m         *Client
+		response  string
+		want      *QueryStats
+		wantParam string
+	}{
+		{
+			name:      "Test QueryAt stats unicorn path",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30, queryStats: true},
+			response:  statsResponse,
+			want:      &QueryStats{TotalQueryableSamples: 1500, PeakSamples: 42},
+			wantParam: "all",
+		},
+		{
+			name:      "Test QueryAt stats missing in response",
+			m:         &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30, queryStats: true},
+			response:  string(unicornResponse),
+			wantParam: "all",
+		},
+		{
+			name:     "Test QueryAt stats disabled",
+			m:        &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			response: statsResponse,
+		},
+	}
+	for _, tt := range tests {
+		var gotParam string
+		httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotParam = r.URL.Query().Get("stats")
+			fmt.Fprint(w, tt.response)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.QueryAt("QUERY", time.Time{})
+			if err != nil {
+				t.Errorf("Client.QueryAt() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got.Stats, tt.want) {
+				t.Errorf("Client.QueryAt() Stats = %v, want %v", got.Stats, tt.want)
+			}
+			if gotParam != tt.wantParam {
+				t.Errorf("Client.QueryAt() stats param = %v, want %v", gotParam, tt.wantParam)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestWithQueryStats(t *testing.T) {
+	m := NewClient("http", "127.0.0.1", "9090", WithQueryStats())
+	if !m.queryStats {
+		t.Errorf("WithQueryStats() queryStats = false, want true")
+	}
+}
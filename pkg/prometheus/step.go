@@ -0,0 +1,54 @@
+package prometheus
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// suggestedStepPattern matches step suggested in error message, e.g. "(?step=1m)"
+	suggestedStepPattern = regexp.MustCompile(`step=([0-9]+(?:\.[0-9]+)?(?:ms|s|m|h)?)\b`)
+	// maxResolutionPattern matches Prometheus error of too dense range query
+	maxResolutionPattern = regexp.MustCompile(`maximum resolution of ([0-9,]+) points`)
+)
+
+// WithFollowStepSuggestion re-issues range query rejected as too dense once with step suggested by server,
+// explicit step=<duration> in error message is used, otherwise step is derived from maximum resolution
+// Prometheus reports ("exceeded maximum resolution of 11,000 points per timeseries")
+func WithFollowStepSuggestion() Option {
+	return func(args *Client) {
+		args.followStepSuggestion = true
+	}
+}
+
+// suggestedStep returns step suggested by server error of range query over [start, end]
+func suggestedStep(err error, start, end time.Time) (time.Duration, bool) {
+	apiErr, ok := errors.Cause(err).(*APIError)
+	if !ok || apiErr.Type != "bad_data" {
+		return 0, false
+	}
+
+	if match := suggestedStepPattern.FindStringSubmatch(apiErr.Message); match != nil {
+		if seconds, err := strconv.ParseFloat(match[1], 64); err == nil {
+			return secondsToDuration(seconds), true
+		}
+		if step, err := time.ParseDuration(match[1]); err == nil {
+			return step, true
+		}
+	}
+
+	if match := maxResolutionPattern.FindStringSubmatch(apiErr.Message); match != nil {
+		points, err := strconv.Atoi(strings.Replace(match[1], ",", "", -1))
+		if err != nil || points <= 0 {
+			return 0, false
+		}
+		return time.Duration(math.Ceil(end.Sub(start).Seconds()/float64(points))) * time.Second, true
+	}
+
+	return 0, false
+}
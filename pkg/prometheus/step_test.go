@@ -0,0 +1,96 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestWithFollowStepSuggestion(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+	start := time.Unix(1500000000, 0)
+
+	// fineStepHandler rejects steps finer than 30s the way Prometheus rejects too dense queries
+	fineStepHandler := func(message string) func(w http.ResponseWriter, r *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			step, err := time.ParseDuration(r.URL.Query().Get("step"))
+			if err != nil || step < 30*time.Second {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, `{"status":"error","errorType":"bad_data","error":%q}`, message)
+				return
+			}
+			fmt.Fprint(w, `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[1500000000,"1"]]}]}}`)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		m         *Client
+		end       time.Time
+		handler   func(w http.ResponseWriter, r *http.Request)
+		wantSteps []string
+		wantErr   bool
+	}{
+		{
+			name:      "Test WithFollowStepSuggestion explicit suggestion",
+			m:         NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithFollowStepSuggestion()),
+			end:       start.Add(time.Hour),
+			handler:   fineStepHandler("query too dense, try step=1m"),
+			wantSteps: []string{"1s", "1m"},
+		},
+		{
+			name:      "Test WithFollowStepSuggestion maximum resolution",
+			m:         NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithFollowStepSuggestion()),
+			end:       start.Add(100 * time.Hour),
+			handler:   fineStepHandler("exceeded maximum resolution of 11,000 points per timeseries. Try decreasing the query resolution (?step=XX)"),
+			wantSteps: []string{"1s", "33s"},
+		},
+		{
+			name:      "Test WithFollowStepSuggestion suggestion still rejected",
+			m:         NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithFollowStepSuggestion()),
+			end:       start.Add(time.Hour),
+			handler:   fineStepHandler("query too dense, try step=10s"),
+			wantSteps: []string{"1s", "10s"},
+			wantErr:   true,
+		},
+		{
+			name:      "Test WithFollowStepSuggestion no suggestion",
+			m:         NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithFollowStepSuggestion()),
+			end:       start.Add(time.Hour),
+			handler:   fineStepHandler("query too dense"),
+			wantSteps: []string{"1s"},
+			wantErr:   true,
+		},
+		{
+			name:      "Test WithFollowStepSuggestion disabled",
+			m:         NewClient("http", "127.0.0.1", "9090", WithLogger(logger)),
+			end:       start.Add(time.Hour),
+			handler:   fineStepHandler("query too dense, try step=1m"),
+			wantSteps: []string{"1s"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		var gotSteps []string
+		httpServer := startHTTPServer("/api/v1/query_range", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotSteps = append(gotSteps, r.URL.Query().Get("step"))
+			tt.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.m.QueryRangeRequest("QUERY", start, tt.end, time.Second)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRangeRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if fmt.Sprint(gotSteps) != fmt.Sprint(tt.wantSteps) {
+				t.Errorf("Client.QueryRangeRequest() steps = %v, want %v", gotSteps, tt.wantSteps)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
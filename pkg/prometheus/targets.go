@@ -0,0 +1,65 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// TargetStateActive returns active targets only
+	TargetStateActive = "active"
+	// TargetStateDropped returns dropped targets only
+	TargetStateDropped = "dropped"
+	// TargetStateAny returns both active and dropped targets
+	TargetStateAny = "any"
+)
+
+// Targets Prometheus scrape targets
+type Targets struct {
+	ActiveTargets  []Target `json:"activeTargets"`
+	DroppedTargets []Target `json:"droppedTargets"`
+}
+
+// Target Prometheus scrape target, dropped targets carry DiscoveredLabels only
+type Target struct {
+	DiscoveredLabels map[string]string `json:"discoveredLabels"`
+	Labels           map[string]string `json:"labels"`
+	ScrapePool       string            `json:"scrapePool"`
+	ScrapeURL        string            `json:"scrapeUrl"`
+	Health           string            `json:"health"`
+	LastError        string            `json:"lastError"`
+	LastScrape       time.Time         `json:"lastScrape"`
+}
+
+// Targets returns Prometheus scrape targets
+// param: state - TargetStateActive, TargetStateDropped or TargetStateAny filters targets server-side, empty string uses server default
+// result: *Targets - active and dropped targets
+func (m *Client) Targets(state string) (*Targets, error) {
+	params := url.Values{}
+
+	switch state {
+	case "":
+	case TargetStateActive, TargetStateDropped, TargetStateAny:
+		params.Set("state", state)
+	default:
+		return nil, errors.Errorf("%v: Unknown target state %v", funcInfo(), state)
+	}
+
+	data, err := m.getData(context.Background(), "targets", params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: targets request failed", funcInfo())
+	}
+
+	var targets Targets
+
+	err = json.Unmarshal(data, &targets)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v: targets unmarshal failed", funcInfo())
+	}
+
+	return &targets, nil
+}
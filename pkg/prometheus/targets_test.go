@@ -0,0 +1,132 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var targetsResponse = `{"status":"success","data":{"activeTargets":[` +
+	`{"discoveredLabels":{"__address__":"127.0.0.1:9090","job":"prometheus"},` +
+	`"labels":{"instance":"127.0.0.1:9090","job":"prometheus"},"scrapePool":"prometheus","scrapeUrl":"http://127.0.0.1:9090/metrics",` +
+	`"globalUrl":"http://example-prometheus:9090/metrics","lastError":"","lastScrape":"2017-01-17T15:07:44.723715405+01:00",` +
+	`"lastScrapeDuration":0.050688943,"health":"up","scrapeInterval":"1m","scrapeTimeout":"10s"},` +
+	`{"discoveredLabels":{"__address__":"127.0.0.1:9100","job":"node"},` +
+	`"labels":{"instance":"127.0.0.1:9100","job":"node"},"scrapePool":"node","scrapeUrl":"http://127.0.0.1:9100/metrics",` +
+	`"lastError":"connection refused","lastScrape":"2017-01-17T14:07:44Z","health":"down"}],` +
+	`"droppedTargets":[{"discoveredLabels":{"__address__":"127.0.0.1:9100","job":"dropped"}}]}}`
+
+func TestClient_Targets(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	want := &Targets{
+		ActiveTargets: []Target{
+			{
+				DiscoveredLabels: map[string]string{"__address__": "127.0.0.1:9090", "job": "prometheus"},
+				Labels:           map[string]string{"instance": "127.0.0.1:9090", "job": "prometheus"},
+				ScrapePool:       "prometheus",
+				ScrapeURL:        "http://127.0.0.1:9090/metrics",
+				Health:           "up",
+				LastScrape:       time.Date(2017, 1, 17, 14, 7, 44, 723715405, time.UTC),
+			},
+			{
+				DiscoveredLabels: map[string]string{"__address__": "127.0.0.1:9100", "job": "node"},
+				Labels:           map[string]string{"instance": "127.0.0.1:9100", "job": "node"},
+				ScrapePool:       "node",
+				ScrapeURL:        "http://127.0.0.1:9100/metrics",
+				Health:           "down",
+				LastError:        "connection refused",
+				LastScrape:       time.Date(2017, 1, 17, 14, 7, 44, 0, time.UTC),
+			},
+		},
+		DroppedTargets: []Target{{DiscoveredLabels: map[string]string{"__address__": "127.0.0.1:9100", "job": "dropped"}}},
+	}
+
+	tests := []struct {
+		name      string
+		m         *Client
+		state     string
+		handler   func(w http.ResponseWriter, r *http.Request)
+		want      *Targets
+		wantState string
+		wantErr   bool
+	}{
+		{
+			name: "Test Targets unicorn path",
+			m:    &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, targetsResponse)
+			},
+			want: want,
+		},
+		{
+			name:  "Test Targets state any",
+			m:     &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			state: TargetStateAny,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, targetsResponse)
+			},
+			want:      want,
+			wantState: "any",
+		},
+		{
+			name:  "Test Targets state dropped",
+			m:     &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			state: TargetStateDropped,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"status":"success","data":{"activeTargets":[],"droppedTargets":[{"discoveredLabels":{"job":"dropped"}}]}}`)
+			},
+			want:      &Targets{ActiveTargets: []Target{}, DroppedTargets: []Target{{DiscoveredLabels: map[string]string{"job": "dropped"}}}},
+			wantState: "dropped",
+		},
+		{
+			name:    "Test Targets unknown state",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			state:   "unknown",
+			handler: dataFailhandler,
+			wantErr: true,
+		},
+		{
+			name:    "Test Targets data fail",
+			m:       &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30},
+			handler: dataFailhandler,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		var gotState string
+		httpServer := startHTTPServer("/api/v1/targets", "9090", func(w http.ResponseWriter, r *http.Request) {
+			gotState = r.URL.Query().Get("state")
+			tt.handler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Targets(tt.state)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Targets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			for _, targets := range [][]Target{got.ActiveTargets, got.DroppedTargets} {
+				for i := range targets {
+					targets[i].LastScrape = targets[i].LastScrape.UTC()
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.Targets() got = %+v, want %+v", got, tt.want)
+			}
+			if gotState != tt.wantState {
+				t.Errorf("Client.Targets() state param = %v, want %v", gotState, tt.wantState)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
@@ -0,0 +1,63 @@
+package prometheus
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// CheckThreshold runs single-value query and evaluates it against threshold, e.g. for synthetic monitoring
+// param: query     - Prometheus query string, must return scalar or single-element vector
+// param: op        - comparison operator applied as "<value> op <threshold>": ">", ">=", "<", "<=" or "=="
+// param: threshold - value compared against
+// result: bool    - whether the condition holds, NaN value never satisfies it
+// result: float64 - actual value of the query
+func (m *Client) CheckThreshold(query string, op string, threshold float64) (bool, float64, error) {
+	compare, ok := thresholdOperators[op]
+	if !ok {
+		return false, 0, errors.Errorf("%v: Unknown operator %q", funcInfo(), op)
+	}
+
+	value, err := m.querySingleValue(query)
+	if err != nil {
+		return false, 0, errors.Wrapf(err, "%v: query failed", funcInfo())
+	}
+
+	return compare(value, threshold), value, nil
+}
+
+var thresholdOperators = map[string]func(value, threshold float64) bool{
+	">":  func(value, threshold float64) bool { return value > threshold },
+	">=": func(value, threshold float64) bool { return value >= threshold },
+	"<":  func(value, threshold float64) bool { return value < threshold },
+	"<=": func(value, threshold float64) bool { return value <= threshold },
+	"==": func(value, threshold float64) bool { return value == threshold },
+}
+
+// querySingleValue runs instant query returning value of scalar or single-element vector result
+func (m *Client) querySingleValue(query string) (float64, error) {
+	resp, resultType, err := m.QueryRequest(query)
+	if err != nil {
+		return 0, errors.Wrapf(err, "%v: query request failed", funcInfo())
+	}
+
+	switch resultType {
+	case ResultTypeScalar:
+		var pair SamplePair
+		if err := json.Unmarshal(resp, &pair); err != nil {
+			return 0, errors.Wrapf(err, "%v: scalar decoding failed", funcInfo())
+		}
+		return pair.Value, nil
+	case ResultTypeVector:
+		samples, err := decodeVector(resp, m.maxResultSeries)
+		if err != nil {
+			return 0, errors.Wrapf(err, "%v: vector decoding failed", funcInfo())
+		}
+		if len(samples) != 1 {
+			return 0, errors.Errorf("%v: Vector has %v elements, expected 1", funcInfo(), len(samples))
+		}
+		return samples[0].Value, nil
+	default:
+		return 0, errors.Wrapf(ErrUnexpectedResultType, "%v: got %v", funcInfo(), resultType)
+	}
+}
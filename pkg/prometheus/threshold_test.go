@@ -0,0 +1,63 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestClient_CheckThreshold(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	vectorResponse := []byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1500000000,"0.5"]}]}}`)
+	scalarResponse := []byte(`{"status":"success","data":{"resultType":"scalar","result":[1500000000,"0.5"]}}`)
+
+	type args struct {
+		op        string
+		threshold float64
+		handler   func(w http.ResponseWriter, r *http.Request)
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      bool
+		wantValue float64
+		wantErr   bool
+	}{
+		{name: "Test CheckThreshold > passing", args: args{op: ">", threshold: 0.4, handler: responseHandler(vectorResponse)}, want: true, wantValue: 0.5},
+		{name: "Test CheckThreshold > failing", args: args{op: ">", threshold: 0.5, handler: responseHandler(vectorResponse)}, wantValue: 0.5},
+		{name: "Test CheckThreshold >= passing", args: args{op: ">=", threshold: 0.5, handler: responseHandler(vectorResponse)}, want: true, wantValue: 0.5},
+		{name: "Test CheckThreshold >= failing", args: args{op: ">=", threshold: 0.6, handler: responseHandler(vectorResponse)}, wantValue: 0.5},
+		{name: "Test CheckThreshold < passing", args: args{op: "<", threshold: 0.6, handler: responseHandler(vectorResponse)}, want: true, wantValue: 0.5},
+		{name: "Test CheckThreshold < failing", args: args{op: "<", threshold: 0.5, handler: responseHandler(vectorResponse)}, wantValue: 0.5},
+		{name: "Test CheckThreshold <= passing", args: args{op: "<=", threshold: 0.5, handler: responseHandler(vectorResponse)}, want: true, wantValue: 0.5},
+		{name: "Test CheckThreshold <= failing", args: args{op: "<=", threshold: 0.4, handler: responseHandler(vectorResponse)}, wantValue: 0.5},
+		{name: "Test CheckThreshold == passing", args: args{op: "==", threshold: 0.5, handler: responseHandler(vectorResponse)}, want: true, wantValue: 0.5},
+		{name: "Test CheckThreshold == failing", args: args{op: "==", threshold: 1, handler: responseHandler(vectorResponse)}, wantValue: 0.5},
+		{name: "Test CheckThreshold scalar result", args: args{op: "<", threshold: 1, handler: responseHandler(scalarResponse)}, want: true, wantValue: 0.5},
+		{name: "Test CheckThreshold unknown operator", args: args{op: "!=", threshold: 1, handler: responseHandler(vectorResponse)}, wantErr: true},
+		{name: "Test CheckThreshold multiple elements", args: args{op: ">", threshold: 1, handler: responseHandler(latestVectorResponse)}, wantErr: true},
+		{name: "Test CheckThreshold matrix result", args: args{op: ">", threshold: 1, handler: responseHandler(latestMatrixResponse)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query", "9090", tt.args.handler)
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Client{protocol: "http", address: "127.0.0.1", port: "9090", logger: NewZapLogger(logger), timeout: time.Second * 30}
+
+			got, gotValue, err := m.CheckThreshold("QUERY", tt.args.op, tt.args.threshold)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.CheckThreshold() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want || gotValue != tt.wantValue {
+				t.Errorf("Client.CheckThreshold() = %v, %v, want %v, %v", got, gotValue, tt.want, tt.wantValue)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
@@ -0,0 +1,113 @@
+package prometheus
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Defaults of http.DefaultTransport used for transport phases not configured by options
+const (
+	defaultDialTimeout         = 30 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+// WithDialTimeout sets timeout of establishing TCP connection to Prometheus, 30s is used when not set.
+// Transport options are ignored when WithHTTPClient is set.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(args *Client) {
+		args.dialTimeout = timeout
+	}
+}
+
+// WithTLSHandshakeTimeout sets timeout of TLS handshake with Prometheus, 10s is used when not set.
+// Transport options are ignored when WithHTTPClient is set.
+func WithTLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(args *Client) {
+		args.tlsHandshakeTimeout = timeout
+	}
+}
+
+// WithResponseHeaderTimeout sets timeout of waiting for response headers once request is sent,
+// failing fast on server stuck before answering while slow body of large result is still bounded
+// only by WithTimeout. Transport options are ignored when WithHTTPClient is set.
+func WithResponseHeaderTimeout(timeout time.Duration) Option {
+	return func(args *Client) {
+		args.responseHeaderTimeout = timeout
+	}
+}
+
+// WithTLSConfig sets TLS config of connections to Prometheus served over https, e.g. custom RootCAs
+// for internal CA or Certificates for client certificate authentication. Config is cloned, so later
+// changes of it do not affect Client. Transport options are ignored when WithHTTPClient is set.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(args *Client) {
+		args.tlsConfig = config.Clone()
+	}
+}
+
+// WithInsecureSkipVerify disables verification of Prometheus server certificate and host name.
+// UNSAFE: connection is open to man-in-the-middle attacks, use only in development environments,
+// prefer WithTLSConfig with RootCAs trusting the server. Transport options are ignored when WithHTTPClient is set.
+func WithInsecureSkipVerify() Option {
+	return func(args *Client) {
+		args.insecureSkipVerify = true
+	}
+}
+
+// transport returns transport configured by transport options, nil when none is set so http.DefaultTransport is used
+func (m *Client) transport() http.RoundTripper {
+	if m.dialTimeout == 0 && m.tlsHandshakeTimeout == 0 && m.responseHeaderTimeout == 0 &&
+		m.tlsConfig == nil && !m.insecureSkipVerify {
+		return nil
+	}
+
+	dialTimeout := m.dialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = defaultDialTimeout
+	}
+
+	tlsHandshakeTimeout := m.tlsHandshakeTimeout
+	if tlsHandshakeTimeout == 0 {
+		tlsHandshakeTimeout = defaultTLSHandshakeTimeout
+	}
+
+	var tlsConfig *tls.Config
+	if m.tlsConfig != nil || m.insecureSkipVerify {
+		tlsConfig = m.tlsConfig.Clone()
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		if m.insecureSkipVerify {
+			tlsConfig.InsecureSkipVerify = true
+		}
+	}
+
+	// keeps http.DefaultTransport defaults such as proxy, idle pool and HTTP/2, apart from configured timeouts and TLS config
+	transport := defaultTransport()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSClientConfig = tlsConfig
+	transport.TLSHandshakeTimeout = tlsHandshakeTimeout
+	transport.ResponseHeaderTimeout = m.responseHeaderTimeout
+
+	return transport
+}
+
+// defaultTransport returns clone of http.DefaultTransport, or transport with its defaults when it was replaced
+func defaultTransport() *http.Transport {
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		return transport.Clone()
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
@@ -0,0 +1,209 @@
+package prometheus
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+func TestWithResponseHeaderTimeout(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name    string
+		m       *Client
+		delay   time.Duration
+		wantErr bool
+	}{
+		{
+			name:    "Test WithResponseHeaderTimeout trips on delayed headers",
+			m:       NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithResponseHeaderTimeout(time.Millisecond*50)),
+			delay:   time.Millisecond * 500,
+			wantErr: true,
+		},
+		{
+			name:  "Test WithResponseHeaderTimeout headers in time",
+			m:     NewClient("http", "127.0.0.1", "9090", WithLogger(logger), WithResponseHeaderTimeout(time.Second*5)),
+			delay: time.Millisecond * 10,
+		},
+		{
+			name:  "Test WithResponseHeaderTimeout not set",
+			m:     NewClient("http", "127.0.0.1", "9090", WithLogger(logger)),
+			delay: time.Millisecond * 100,
+		},
+	}
+	for _, tt := range tests {
+		httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(tt.delay)
+			unicornHandler(w, r)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.m.QueryRequest("QUERY")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Client.QueryRequest() error = %v, want %v", err, context.DeadlineExceeded)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+func TestClient_transport(t *testing.T) {
+	tests := []struct {
+		name                      string
+		m                         *Client
+		wantDefault               bool
+		wantTLSHandshakeTimeout   time.Duration
+		wantResponseHeaderTimeout time.Duration
+	}{
+		{
+			name:        "Test transport default",
+			m:           NewClient("http", "127.0.0.1", "9090"),
+			wantDefault: true,
+		},
+		{
+			name:                    "Test transport TLS handshake timeout",
+			m:                       NewClient("http", "127.0.0.1", "9090", WithTLSHandshakeTimeout(time.Second)),
+			wantTLSHandshakeTimeout: time.Second,
+		},
+		{
+			name:                      "Test transport dial timeout keeps other defaults",
+			m:                         NewClient("http", "127.0.0.1", "9090", WithDialTimeout(time.Second)),
+			wantTLSHandshakeTimeout:   defaultTLSHandshakeTimeout,
+			wantResponseHeaderTimeout: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := tt.m.client().Transport
+			if tt.wantDefault {
+				if transport != nil {
+					t.Errorf("Client.client() transport = %v, want default", transport)
+				}
+				return
+			}
+
+			httpTransport, ok := transport.(*http.Transport)
+			if !ok {
+				t.Fatalf("Client.client() transport = %T, want *http.Transport", transport)
+			}
+			if httpTransport.TLSHandshakeTimeout != tt.wantTLSHandshakeTimeout {
+				t.Errorf("TLSHandshakeTimeout = %v, want %v", httpTransport.TLSHandshakeTimeout, tt.wantTLSHandshakeTimeout)
+			}
+			if httpTransport.ResponseHeaderTimeout != tt.wantResponseHeaderTimeout {
+				t.Errorf("ResponseHeaderTimeout = %v, want %v", httpTransport.ResponseHeaderTimeout, tt.wantResponseHeaderTimeout)
+			}
+		})
+	}
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	server := httptest.NewTLSServer(http.HandlerFunc(unicornHandler))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{
+			name:    "Test WithTLSConfig unknown authority",
+			wantErr: true,
+		},
+		{
+			name: "Test WithTLSConfig custom RootCAs",
+			opts: []Option{WithTLSConfig(&tls.Config{RootCAs: roots})},
+		},
+		{
+			name: "Test WithInsecureSkipVerify",
+			opts: []Option{WithInsecureSkipVerify()},
+		},
+		{
+			name: "Test WithInsecureSkipVerify with TLS config",
+			opts: []Option{WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}), WithInsecureSkipVerify()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewClient("https", u.Hostname(), u.Port(), append([]Option{WithLogger(logger)}, tt.opts...)...)
+
+			_, _, err := m.QueryRequest("QUERY")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.QueryRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithTLSConfig_cloned(t *testing.T) {
+	config := &tls.Config{ServerName: "prometheus"}
+	m := NewClient("https", "127.0.0.1", "9090", WithTLSConfig(config))
+	config.ServerName = "changed"
+
+	transport := m.client().Transport.(*http.Transport)
+	if transport.TLSClientConfig.ServerName != "prometheus" {
+		t.Errorf("TLSClientConfig.ServerName = %v, want %v", transport.TLSClientConfig.ServerName, "prometheus")
+	}
+}
+
+func TestClient_transport_http2(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	var proto string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto = r.Proto
+		unicornHandler(w, r)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{
+			name: "Test transport HTTP/2 with TLS config",
+			opts: []Option{WithTLSConfig(&tls.Config{RootCAs: roots})},
+		},
+		{
+			name: "Test transport HTTP/2 with timeouts",
+			opts: []Option{WithInsecureSkipVerify(), WithDialTimeout(time.Second), WithResponseHeaderTimeout(time.Second)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewClient("https", u.Hostname(), u.Port(), append([]Option{WithLogger(logger)}, tt.opts...)...)
+
+			if _, _, err := m.QueryRequest("QUERY"); err != nil {
+				t.Fatalf("Client.QueryRequest() error = %v", err)
+			}
+			if proto != "HTTP/2.0" {
+				t.Errorf("Client.QueryRequest() protocol = %v, want HTTP/2.0", proto)
+			}
+		})
+	}
+}
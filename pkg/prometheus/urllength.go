@@ -0,0 +1,79 @@
+package prometheus
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// maxProbeURLLength upper bound of URL length probed by DetectMaxURLLength
+const maxProbeURLLength = 64 * 1024
+
+// defaultMaxURLLength URL length requests are sent as POST above unless DetectMaxURLLength found actual limit,
+// common default of servers and proxies
+const defaultMaxURLLength = 8 * 1024
+
+// DetectMaxURLLength returns maximum length of query URL accepted by server and proxies in between,
+// found by binary search with dummy instant queries. Result is cached after first successful detection.
+// result: int - maximum accepted URL length in bytes, capped at 64KiB
+func (m *Client) DetectMaxURLLength() (int, error) {
+	if length := atomic.LoadInt32(&m.maxURLLength); length > 0 {
+		return int(length), nil
+	}
+
+	ctx := context.Background()
+	base := m.apiURL("query", m.queryParams("vector(0)")) + "&pad="
+
+	accepted, err := m.urlAccepted(ctx, base)
+	if err != nil {
+		return 0, errors.Wrapf(err, "%v: probing minimal URL failed", funcInfo())
+	}
+	if !accepted {
+		return 0, errors.Errorf("%v: Server rejects minimal URL of length %v", funcInfo(), len(base))
+	}
+
+	low, high := len(base), maxProbeURLLength+1
+	for high-low > 1 {
+		mid := low + (high-low)/2
+
+		accepted, err := m.urlAccepted(ctx, base+strings.Repeat("x", mid-len(base)))
+		if err != nil {
+			return 0, errors.Wrapf(err, "%v: probing URL of length %v failed", funcInfo(), mid)
+		}
+
+		if accepted {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	atomic.StoreInt32(&m.maxURLLength, int32(low))
+
+	return low, nil
+}
+
+// urlAccepted reports whether server accepted URL, i.e. did not answer 414 or 431
+func (m *Client) urlAccepted(ctx context.Context, rawURL string) (bool, error) {
+	resp, err := m.getURL(ctx, rawURL)
+	if err != nil {
+		return false, errors.Wrapf(err, "%v: probe request failed", funcInfo())
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
+		return false, errors.Wrapf(err, "%v: draining response body failed", funcInfo())
+	}
+
+	switch resp.StatusCode {
+	case http.StatusRequestURITooLong, http.StatusRequestHeaderFieldsTooLarge:
+		return false, nil
+	default:
+		return true, nil
+	}
+}
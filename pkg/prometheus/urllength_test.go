@@ -0,0 +1,83 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestClient_DetectMaxURLLength(t *testing.T) {
+	logger := zap.NewExample(zap.Development())
+
+	tests := []struct {
+		name      string
+		limit     int
+		wantLimit bool
+		wantErr   bool
+	}{
+		{
+			name:      "Test DetectMaxURLLength 4KB proxy",
+			limit:     4096,
+			wantLimit: true,
+		},
+		{
+			name:      "Test DetectMaxURLLength 8KB proxy",
+			limit:     8192,
+			wantLimit: true,
+		},
+		{
+			name:  "Test DetectMaxURLLength unlimited",
+			limit: 0,
+		},
+		{
+			name:    "Test DetectMaxURLLength minimal URL rejected",
+			limit:   10,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		var requests int
+		httpServer := startHTTPServer("/api/v1/query", "9090", func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if tt.limit > 0 && len(r.URL.RequestURI()) > tt.limit {
+				w.WriteHeader(http.StatusRequestURITooLong)
+				return
+			}
+			fmt.Fprint(w, unicornResponse)
+		})
+
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewClient("http", "127.0.0.1", "9090", WithLogger(logger))
+
+			want := maxProbeURLLength
+			if tt.wantLimit {
+				want = len(m.baseURL()) + tt.limit
+			}
+
+			got, err := m.DetectMaxURLLength()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.DetectMaxURLLength() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != want {
+				t.Errorf("Client.DetectMaxURLLength() = %v, want %v", got, want)
+			}
+
+			probes := requests
+			if cached, err := m.DetectMaxURLLength(); err != nil || cached != got {
+				t.Errorf("Client.DetectMaxURLLength() cached = %v, %v, want %v", cached, err, got)
+			}
+			if requests != probes {
+				t.Errorf("Client.DetectMaxURLLength() sent %v requests for cached length", requests-probes)
+			}
+		})
+
+		httpServer.Shutdown(context.Background())
+	}
+}